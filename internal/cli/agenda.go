@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/ui"
+)
+
+// agendaCmd represents the agenda command for dated notes
+var agendaCmd = &cobra.Command{
+	Use:     "agenda",
+	Aliases: []string{"ag"},
+	Short:   "Show overdue notes and what is due or scheduled this week",
+	Long: `Show overdue notes, then every note due or scheduled today and over
+the next 7 days, grouped by day. Done notes past their due date are not
+overdue. Use cx calendar for a month grid you can move notes around in.
+
+Examples:
+  cx agenda
+  cx agenda --days 14`,
+	Run: func(cmd *cobra.Command, args []string) {
+		days, _ := cmd.Flags().GetInt("days")
+		if days < 0 {
+			fmt.Printf("❌ Invalid number of days: %d\n", days)
+			os.Exit(1)
+		}
+
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+		overdue, err := db.GetOverdueNotes(today)
+		if err != nil {
+			fmt.Printf("❌ Error fetching notes: %v\n", err)
+			os.Exit(1)
+		}
+
+		upcoming, err := db.GetNotesByDateRange(today, today.AddDate(0, 0, days+1))
+		if err != nil {
+			fmt.Printf("❌ Error fetching notes: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(ui.RenderAgenda(overdue, upcoming, today, days))
+	},
+}
+
+// calendarCmd represents the calendar command for the month grid
+var calendarCmd = &cobra.Command{
+	Use:     "calendar",
+	Aliases: []string{"cal"},
+	Short:   "Open a month calendar of dated notes",
+	Long: `Open an interactive month grid showing the notes due or scheduled on
+each day. Move between days with the arrow keys and between a day's notes
+with tab. Shift+arrows (or H/J/K/L) move the selected note to another
+day, changing its due or scheduled date. Press a to add a note scheduled
+on the selected day.
+
+Examples:
+  cx calendar
+  cx cal`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := ui.StartCalendar(db); err != nil {
+			fmt.Printf("❌ Error starting calendar: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	agendaCmd.Flags().Int("days", 7, "Number of days after today to show")
+}
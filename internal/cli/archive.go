@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/ui"
+)
+
+// archiveCmd represents the archive command
+var archiveCmd = &cobra.Command{
+	Use:   "archive [id...]",
+	Short: "Take notes off the kanban board",
+	Long: `Archive notes, taking them off the kanban board. Archived notes are
+kept, and still found by cx search and cx browse; list them with
+cx list --archived and put them back with cx unarchive.
+
+Examples:
+  cx archive 42
+  cx archive 42 43 51`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setArchived(args, true)
+	},
+}
+
+// unarchiveCmd represents the unarchive command
+var unarchiveCmd = &cobra.Command{
+	Use:   "unarchive [id...]",
+	Short: "Put archived notes back on the kanban board",
+	Long: `Put archived notes back on the kanban board, in the status they were
+archived in. List archived notes with cx list --archived.
+
+Examples:
+  cx unarchive 42
+  cx unarchive 42 43 51`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setArchived(args, false)
+	},
+}
+
+// setArchived archives or unarchives the notes with the given IDs,
+// skipping notes that already are
+func setArchived(args []string, archived bool) {
+	verb := "Unarchived"
+	if archived {
+		verb = "Archived"
+	}
+
+	var ids []int
+	var labels []string
+	for _, arg := range args {
+		id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil {
+			fmt.Printf("❌ Invalid note ID: %s\n", arg)
+			os.Exit(1)
+		}
+
+		note, err := db.GetNote(id)
+		if err != nil {
+			fmt.Printf("❌ Error fetching note: %v\n", err)
+			os.Exit(1)
+		}
+		if (note.ArchivedAt != nil) == archived {
+			fmt.Printf("📦 #%d is already %s\n", id, strings.ToLower(verb))
+			continue
+		}
+		ids = append(ids, id)
+		labels = append(labels, fmt.Sprintf("#%d", id))
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	if err := db.SetNotesArchived(ids, archived); err != nil {
+		fmt.Printf("❌ Error updating notes: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %s %s\n", verb, strings.Join(labels, ", "))
+}
+
+// listArchived lists the archived notes, most recently archived first
+func listArchived() {
+	notes, err := db.GetArchivedNotes()
+	if err != nil {
+		fmt.Printf("❌ Error fetching notes: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(notes) == 0 {
+		fmt.Println("📦 No archived notes")
+		return
+	}
+
+	fmt.Println(ui.RenderNotesList(notes, "Archived Notes"))
+}
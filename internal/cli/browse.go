@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/ui"
+)
+
+// browseCmd represents the browse command for the full-screen note browser
+var browseCmd = &cobra.Command{
+	Use:     "browse [query]",
+	Aliases: []string{"b", "br"},
+	Short:   "Browse notes in a full-screen list",
+	Long: `Browse every note, archived ones included, in a full-screen list with
+a preview of the selected note.
+
+Type / to filter fuzzily: "dplybug" finds "deploy bug". Press o to sort
+by updated, created, status or priority (most urgent first), and O to
+reverse. From the list you can edit (e), delete (d), change status
+(1/2/3), show related notes (r) and copy a note's ID to the clipboard (y).
+
+Examples:
+  cx browse
+  cx browse deploy          # Start filtered
+  cx br --sort created
+  cx br --sort priority`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sortBy, _ := cmd.Flags().GetString("sort")
+
+		opts := ui.BrowseOptions{
+			Sort:  sortBy,
+			Query: strings.Join(args, " "),
+		}
+		if err := ui.StartBrowse(db, opts); err != nil {
+			fmt.Printf("❌ Error starting browser: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	browseCmd.Flags().String("sort", "updated", "Sort order: updated, created, status or priority")
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/storage"
+)
+
+// checkCmd represents the check command for checklist items
+var checkCmd = &cobra.Command{
+	Use:   "check [id] [item]",
+	Short: "Check or uncheck a checklist item in a note",
+	Long: `Check or uncheck an item of a note's checklist, written in its content as
+markdown checkboxes:
+
+  - [ ] Draft the proposal
+  - [x] Book the room
+
+Items are numbered from 1 in the order they appear. Without an item
+number, the checklist is listed with its numbers.
+
+Set "auto_done": true in ~/.cheesebox/config.json to move a note to done
+once every item is checked. On the kanban board, c toggles an item of the
+selected card.
+
+Examples:
+  cx check 42
+  cx check 42 2`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Printf("❌ Invalid note ID: %s\n", args[0])
+			os.Exit(1)
+		}
+
+		if len(args) == 1 {
+			note, err := db.GetNote(id)
+			if err != nil {
+				fmt.Printf("❌ Error fetching note: %v\n", err)
+				os.Exit(1)
+			}
+			if len(storage.ParseChecklist(note.Content)) == 0 {
+				fmt.Printf("📝 #%d has no checklist\n", id)
+				return
+			}
+			printChecklist(note)
+			return
+		}
+
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Printf("❌ Invalid item number: %s\n", args[1])
+			os.Exit(1)
+		}
+
+		before, err := db.GetNote(id)
+		if err != nil {
+			fmt.Printf("❌ Error fetching note: %v\n", err)
+			os.Exit(1)
+		}
+		note, err := db.CheckItem(id, n)
+		if err != nil {
+			fmt.Printf("❌ Error updating checklist: %v\n", err)
+			os.Exit(1)
+		}
+
+		item := storage.ParseChecklist(note.Content)[n-1]
+		verb := "Unchecked"
+		if item.Checked {
+			verb = "Checked"
+		}
+		fmt.Printf("✅ %s item %d of #%d: %s\n", verb, n, id, item.Text)
+		printChecklist(note)
+		if note.Status == "done" && before.Status != "done" {
+			fmt.Printf("🎉 Every item is checked: moved #%d to DONE\n", id)
+		}
+	},
+}
+
+// printChecklist prints a note's checklist items with their numbers and
+// its progress
+func printChecklist(note *storage.Note) {
+	checked, total := storage.ChecklistProgress(note.Content)
+	fmt.Printf("☑️  #%d checklist (%d/%d)\n", note.ID, checked, total)
+	for i, item := range storage.ParseChecklist(note.Content) {
+		box := "☐"
+		if item.Checked {
+			box = "☑"
+		}
+		fmt.Printf("  %d. %s %s\n", i+1, box, item.Text)
+	}
+}
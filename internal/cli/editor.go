@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"cheesebox/internal/storage"
+)
+
+// frontMatterDelimiter opens and closes the header of a note file
+const frontMatterDelimiter = "---"
+
+// noteFile is a note as written in the editor: a front-matter header with
+// its status, tags and due date, then its text
+type noteFile struct {
+	Status string // "" when the header leaves it out
+	Tags   []string
+	DueAt  *time.Time
+	Text   string
+
+	// Which header fields were present
+	hasTags, hasDue bool
+}
+
+// formatNoteFile writes a note as edited in the editor
+func formatNoteFile(note *storage.Note) string {
+	due := ""
+	if note.DueAt != nil {
+		due = note.DueAt.Format("2006-01-02")
+	}
+
+	var file strings.Builder
+	file.WriteString(frontMatterDelimiter + "\n")
+	file.WriteString("status: " + note.Status + "\n")
+	file.WriteString("tags: " + strings.Join(note.Tags, ", ") + "\n")
+	file.WriteString("due: " + due + "\n")
+	file.WriteString(frontMatterDelimiter + "\n")
+	file.WriteString(note.Text())
+	if note.Text() != "" {
+		file.WriteString("\n")
+	}
+	return file.String()
+}
+
+// parseNoteFile reads a note file back. The header is optional; without
+// it the whole file is the note's text.
+func parseNoteFile(content string, now time.Time) (*noteFile, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return &noteFile{Text: content}, nil
+	}
+
+	file := &noteFile{}
+	for i, line := range lines[1:] {
+		if strings.TrimSpace(line) == frontMatterDelimiter {
+			file.Text = strings.Join(lines[i+2:], "\n")
+			return file, nil
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("header line %q is not a field: value pair", line)
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "status":
+			status := strings.ToLower(value)
+			if !storage.IsValidStatus(status) {
+				return nil, fmt.Errorf("invalid status %q (use %s)", value, strings.Join(storage.Statuses, ", "))
+			}
+			file.Status = status
+		case "tags":
+			file.hasTags = true
+			for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+				if tag = strings.ToLower(strings.TrimPrefix(tag, "#")); tag != "" {
+					file.Tags = append(file.Tags, tag)
+				}
+			}
+		case "due":
+			file.hasDue = true
+			if value == "" || value == "none" {
+				continue
+			}
+			date, err := storage.ParseDate(value, now)
+			if err != nil {
+				return nil, fmt.Errorf("invalid due date %q: %w", value, err)
+			}
+			file.DueAt = &date
+		default:
+			return nil, fmt.Errorf("unknown header field %q (use status, tags or due)", strings.TrimSpace(key))
+		}
+	}
+	return nil, fmt.Errorf("the header opened by %s is never closed", frontMatterDelimiter)
+}
+
+// apply returns a copy of note with the file's header and text applied.
+// Inline metadata in the text, such as due:friday, takes precedence over
+// the header, and hashtags in the text are added to the header's tags,
+// which replace the note's.
+func (f *noteFile) apply(note *storage.Note, now time.Time) (*storage.Note, error) {
+	base := *note
+	if f.Status != "" {
+		base.Status = f.Status
+	}
+	if f.hasDue {
+		base.DueAt = f.DueAt
+	}
+
+	edited, err := base.Edit(f.Text, now)
+	if err != nil {
+		return nil, err
+	}
+	if f.hasTags {
+		edited.Tags = mergeTags(f.Tags, storage.ParseTags(f.Text))
+	}
+	return edited, nil
+}
+
+// mergeTags returns the tags in a followed by those in b not already in a
+func mergeTags(a, b []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, tag := range append(append([]string(nil), a...), b...) {
+		if !seen[tag] {
+			seen[tag] = true
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// editNote opens note in the editor and returns the edited copy. changed
+// is false when the file was saved as it was opened. A file left empty,
+// or with no text below its header, aborts with an error; one that cannot
+// be read back can be reopened to fix it.
+func editNote(note *storage.Note) (*storage.Note, bool, error) {
+	initial := formatNoteFile(note)
+	content := initial
+	for {
+		var err error
+		content, err = editText(content)
+		if err != nil {
+			return nil, false, err
+		}
+		if strings.TrimSpace(content) == "" {
+			return nil, false, fmt.Errorf("aborting: the file is empty")
+		}
+		if strings.TrimSpace(content) == strings.TrimSpace(initial) {
+			return note, false, nil
+		}
+
+		now := time.Now()
+		file, err := parseNoteFile(content, now)
+		if err == nil {
+			var edited *storage.Note
+			if edited, err = file.apply(note, now); err == nil {
+				if edited.Title == "" {
+					return nil, false, fmt.Errorf("aborting: the note has no text")
+				}
+				return edited, true, nil
+			}
+		}
+
+		fmt.Printf("❌ %v\n", err)
+		if !confirm("Reopen the editor to fix it?") {
+			return nil, false, fmt.Errorf("aborting: nothing was saved")
+		}
+	}
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to yes
+func confirm(question string) bool {
+	fmt.Printf("%s [Y/n] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR, split
+// into the program and its arguments, falling back to vi
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// editText opens the user's editor on a temporary file holding initial
+// and returns the file's contents once the editor exits
+func editText(initial string) (string, error) {
+	file, err := os.CreateTemp("", "cx-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(initial); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	editor := editorCommand()
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temporary file: %w", err)
+	}
+	return string(data), nil
+}
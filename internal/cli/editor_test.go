@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cheesebox/internal/storage"
+)
+
+// testNow is a Wednesday afternoon, the day tests resolve dates against
+var testNow = time.Date(2026, 10, 14, 15, 30, 0, 0, time.Local)
+
+func TestParseNoteFile(t *testing.T) {
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		content string
+		want    noteFile
+	}{
+		{"no header", "Buy milk\n\nTwo litres", noteFile{Text: "Buy milk\n\nTwo litres"}},
+		{"full header", "---\nstatus: doing\ntags: work, #urgent\ndue: fri\n---\nShip it\n",
+			noteFile{Status: "doing", Tags: []string{"work", "urgent"}, DueAt: &friday, Text: "Ship it\n", hasTags: true, hasDue: true}},
+		{"empty fields", "---\ntags:\ndue: none\n---\nShip it",
+			noteFile{Text: "Ship it", hasTags: true, hasDue: true}},
+		{"case and spacing", "---\r\n  Status :  DONE \r\n\r\nTAGS: a b,,c\r\n---\r\nShip it",
+			noteFile{Status: "done", Tags: []string{"a", "b", "c"}, Text: "Ship it", hasTags: true}},
+		{"fields left out", "---\n---\nShip it", noteFile{Text: "Ship it"}},
+		{"delimiter in the text", "---\nstatus: todo\n---\nAbove\n---\nBelow",
+			noteFile{Status: "todo", Text: "Above\n---\nBelow"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNoteFile(tt.content, testNow)
+			if err != nil {
+				t.Fatalf("parseNoteFile error: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("parseNoteFile = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNoteFileErrors(t *testing.T) {
+	tests := map[string]string{
+		"invalid status":   "---\nstatus: blocked\n---\nShip it",
+		"invalid due date": "---\ndue: someday\n---\nShip it",
+		"unknown field":    "---\nowner: alice\n---\nShip it",
+		"not a field":      "---\njust words\n---\nShip it",
+		"never closed":     "---\nstatus: todo\nShip it",
+	}
+
+	for name, content := range tests {
+		if _, err := parseNoteFile(content, testNow); err == nil {
+			t.Errorf("%s: parseNoteFile(%q) gave no error", name, content)
+		}
+	}
+}
+
+func TestNoteFileRoundTrip(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	note := &storage.Note{
+		Title:   "Release",
+		Content: "- [ ] Tag the build",
+		Status:  "doing",
+		Tags:    []string{"release", "work"},
+		DueAt:   &due,
+	}
+
+	file, err := parseNoteFile(formatNoteFile(note), testNow)
+	if err != nil {
+		t.Fatalf("parseNoteFile error: %v", err)
+	}
+	edited, err := file.apply(note, testNow)
+	if err != nil {
+		t.Fatalf("apply error: %v", err)
+	}
+	if edited.Title != note.Title || edited.Content != note.Content || edited.Status != note.Status {
+		t.Errorf("round trip = %q, %q, %s, want %q, %q, %s",
+			edited.Title, edited.Content, edited.Status, note.Title, note.Content, note.Status)
+	}
+	if !reflect.DeepEqual(edited.Tags, note.Tags) {
+		t.Errorf("round trip tags = %v, want %v", edited.Tags, note.Tags)
+	}
+	if edited.DueAt == nil || !edited.DueAt.Equal(due) {
+		t.Errorf("round trip due = %v, want %v", edited.DueAt, due)
+	}
+}
+
+func TestNoteFileApply(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	note := &storage.Note{Title: "Old", Status: "todo", Tags: []string{"old"}, DueAt: &due}
+
+	content := strings.Join([]string{
+		"---",
+		"status: doing",
+		"tags: work",
+		"due: none",
+		"---",
+		"New title due:fri #urgent",
+		"",
+		"Body",
+	}, "\n")
+	file, err := parseNoteFile(content, testNow)
+	if err != nil {
+		t.Fatalf("parseNoteFile error: %v", err)
+	}
+	edited, err := file.apply(note, testNow)
+	if err != nil {
+		t.Fatalf("apply error: %v", err)
+	}
+
+	if edited.Title != "New title #urgent" || edited.Content != "Body" {
+		t.Errorf("text = %q, %q", edited.Title, edited.Content)
+	}
+	if edited.Status != "doing" {
+		t.Errorf("status = %q, want doing", edited.Status)
+	}
+	if want := []string{"work", "urgent"}; !reflect.DeepEqual(edited.Tags, want) {
+		t.Errorf("tags = %v, want %v", edited.Tags, want)
+	}
+	// Inline metadata takes precedence over the header
+	if friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local); edited.DueAt == nil || !edited.DueAt.Equal(friday) {
+		t.Errorf("due = %v, want %v", edited.DueAt, friday)
+	}
+
+	// The header clears the due date when the text sets none
+	file.Text = "New title"
+	if edited, err = file.apply(note, testNow); err != nil {
+		t.Fatalf("apply error: %v", err)
+	}
+	if edited.DueAt != nil {
+		t.Errorf("due = %v, want none", edited.DueAt)
+	}
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// stdinIsTerminal reports whether standard input is an interactive
+// terminal rather than a pipe, file or heredoc
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// readStdin reads standard input to its end
+func readStdin() (string, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read standard input: %w", err)
+	}
+	return string(data), nil
+}
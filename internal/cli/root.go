@@ -8,12 +8,16 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"cheesebox/internal/config"
 	"cheesebox/internal/storage"
 	"cheesebox/internal/ui"
 	"cheesebox/internal/search"
 )
 
-var db *storage.Storage
+var (
+	db  *storage.Storage
+	cfg *config.Config
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -26,7 +30,8 @@ var rootCmd = &cobra.Command{
 • Apple Notes sync capability
 
 Think of it as "Notion for the terminal" - powerful, fast, and beautiful.`,
-	Run: showRecentNotes,
+	PersistentPreRun: applyTheme,
+	Run:              showRecentNotes,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -39,6 +44,14 @@ func Execute() error {
 	}
 	defer db.Close()
 
+	// Load user settings
+	cfg, err = config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	db.SetAutoTimer(cfg.AutoTimer)
+	db.SetAutoDone(cfg.AutoDone)
+
 	return rootCmd.Execute()
 }
 
@@ -52,9 +65,39 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(embedCmd)
 	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(wipCmd)
+	rootCmd.AddCommand(browseCmd)
+	rootCmd.AddCommand(agendaCmd)
+	rootCmd.AddCommand(calendarCmd)
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(treeCmd)
+	rootCmd.AddCommand(seriesCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(unarchiveCmd)
 	
 	// Global flags
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().String("theme", "", fmt.Sprintf("colour theme: %s or a custom theme from the config", strings.Join(ui.ThemeNames(), ", ")))
+}
+
+// applyTheme switches the interface to the theme named by --theme, or by
+// the config file when the flag isn't given
+func applyTheme(cmd *cobra.Command, args []string) {
+	name, _ := cmd.Flags().GetString("theme")
+	if name == "" {
+		name = cfg.Theme
+	}
+
+	theme, err := ui.ResolveTheme(name, cfg.Themes)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	ui.SetTheme(theme)
 }
 
 // showRecentNotes displays the most recent notes (default command)
@@ -75,49 +118,98 @@ func showRecentNotes(cmd *cobra.Command, args []string) {
 
 // addCmd represents the add command
 var addCmd = &cobra.Command{
-	Use:     "add [content]",
+	Use:     "add [title] [body]",
 	Aliases: []string{"a"},
 	Short:   "Add a new note",
-	Long: `Add a new note to Cheesebox. Content can be provided as an argument 
-or you'll be prompted to enter it interactively.
+	Long: `Add a new note to Cheesebox. The first line of a note is its title,
+shown on cards and in lists, and the lines after it are its body, shown
+in the detail pane.
+
+Give the title and an optional body as arguments, or the whole note as
+one argument spanning several lines. With - in place of the note or
+body, it is read from standard input, so it can come from a pipe or a
+heredoc, as it is when no arguments are given and input is not a
+terminal.
+
+At a terminal, cx add without arguments, or with --editor, opens
+$VISUAL or $EDITOR on a new note. Its header sets the status, tags and
+due date, as described for cx edit; leaving the file empty, or as it
+was opened, adds nothing.
+
+Dates can be written inline: due:<date> sets the due date and @<date>
+the scheduled date, where a date is today, tomorrow, a weekday such as
+friday, an offset like 3d or 2w, next-week, or 2006-01-02. A priority
+is written !p0 (most urgent) to !p3, or !!! for P0 and !! for P1.
+
+every:<rule> makes the note recur: daily, weekly, monthly, weekday,
+weekend, an interval like 2w, weekdays like mon,thu, or an RRULE such as
+FREQ=MONTHLY;BYMONTHDAY=1. Completing a recurring note adds the next
+occurrence to todo, due on the rule's next day. Date, priority and
+recurrence tokens are removed from the saved content.
+
+--parent makes the note a subtask of another, as shown by cx tree.
 
 Examples:
   cx add "Fix authentication bug #urgent"
-  cx a "Team meeting tomorrow #meeting"`,
-	Args: cobra.MaximumNArgs(1),
+  cx add "Release checklist" "- [ ] Tag the build"
+  cx add --editor
+  git log --oneline -5 | cx add "Review recent changes" -
+  cx add <<'EOF'
+  Plan the offsite due:friday
+
+  Book the venue and send the agenda.
+  EOF
+  cx a "Team meeting tomorrow #meeting"
+  cx add "Send the report due:friday"
+  cx add "Plan the offsite @next-week due:2026-11-01"
+  cx add "Renew the domain" --due 2w
+  cx add "Database is down !!!"
+  cx add "Update the docs" -p p2
+  cx add "Water the plants every:2d"
+  cx add "Weekly review every:fri"
+  cx add "Write the migration" --parent 12`,
+	Args: cobra.MaximumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		var content string
-		
-		if len(args) > 0 {
-			content = args[0]
-		} else {
-			// Interactive mode - get content from user
-			fmt.Print("Enter note content: ")
-			var input string
-			fmt.Scanln(&input)
-			content = input
+		note, err := readNote(cmd, args)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
 		}
-
-		if content == "" {
+		if note.Title == "" {
 			fmt.Println("❌ Note content cannot be empty")
 			os.Exit(1)
 		}
 
-		// Extract tags from content
-		tags := storage.ParseTags(content)
-		
-		// Default status
-		status := "todo"
+		// Flags take precedence over inline metadata
+		applyNoteFlags(cmd, note)
+		note.ParentID, _ = parentFlag(cmd)
 		
-		note, err := db.AddNote(content, status, tags)
-		if err != nil {
+		if err := db.CreateNote(note); err != nil {
 			fmt.Printf("❌ Error adding note: %v\n", err)
 			os.Exit(1)
 		}
 
 		fmt.Printf("✅ Note added successfully! ID: %d\n", note.ID)
-		if len(tags) > 0 {
-			fmt.Printf("🏷️  Tags: %s\n", strings.Join(tags, ", "))
+		if note.Status != "todo" {
+			fmt.Printf("📌 Status: %s\n", note.Status)
+		}
+		if len(note.Tags) > 0 {
+			fmt.Printf("🏷️  Tags: %s\n", strings.Join(note.Tags, ", "))
+		}
+		if note.Priority != nil {
+			fmt.Printf("❗ Priority: %s\n", storage.FormatPriority(note.Priority))
+		}
+		if note.DueAt != nil {
+			fmt.Printf("📅 Due: %s\n", note.DueAt.Format("Mon Jan 2, 2006"))
+		}
+		if note.ScheduledAt != nil {
+			fmt.Printf("🗓️  Scheduled: %s\n", note.ScheduledAt.Format("Mon Jan 2, 2006"))
+		}
+		if note.Recurrence != "" {
+			fmt.Printf("🔁 Repeats: every:%s\n", note.Recurrence)
+		}
+		if note.ParentID != 0 {
+			fmt.Printf("🌳 Subtask of #%d\n", note.ParentID)
 		}
 	},
 }
@@ -161,9 +253,29 @@ var kanbanCmd = &cobra.Command{
 	Short:   "Open interactive kanban board",
 	Long: `Open an interactive kanban board to manage your notes across
 todo, doing, and done columns. Use arrow keys to navigate and 
-enter to move notes between columns.`,
+enter to move notes between columns.
+
+Swimlanes split every column by tag, tag prefix, priority (P0-P3)
+or assignee (#@name). Press s on the board to cycle through them.
+Cards are listed oldest first, or most urgent first with --sort priority.
+
+Examples:
+  cx kanban
+  cx kb --swimlanes tag
+  cx kb --swimlanes prefix:area/
+  cx kb --sort priority`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := ui.StartKanban(db); err != nil {
+		swimlanes, _ := cmd.Flags().GetString("swimlanes")
+		order, _ := cmd.Flags().GetString("sort")
+
+		opts := ui.KanbanOptions{
+			Swimlanes: swimlanes,
+			Order:     order,
+			WIPLimits: cfg.WIPLimits,
+			WIPBlock:  cfg.WIPEnforcement == config.WIPBlock,
+			Keys:      cfg.Keys,
+		}
+		if err := ui.StartKanban(db, opts); err != nil {
 			fmt.Printf("❌ Error starting kanban: %v\n", err)
 			os.Exit(1)
 		}
@@ -177,9 +289,35 @@ var editCmd = &cobra.Command{
 	Long: `Edit an existing note by providing its ID.
 You can find note IDs using the list or search commands.
 
+The note opens in $VISUAL or $EDITOR, falling back to vi, below a
+header holding its status, tags and due date:
+
+  ---
+  status: doing
+  tags: backend, urgent
+  due: 2026-11-01
+  ---
+  Fix authentication bug
+
+  The session token expires too early.
+
+Change any of them and save to update the note. An empty due clears
+the date, and hashtags in the text are added to the header's tags. The
+text may also set dates, a priority and a recurrence inline, as with
+cx add. Saving the file unchanged leaves the note as it was, and
+emptying it aborts the edit.
+
+Pass --due, --scheduled, --priority, --every or --parent to change only
+those, without opening the editor; "none" clears them.
+
 Examples:
   cx edit 123
-  cx edit 42`,
+  cx edit 42
+  cx edit 42 --due friday
+  cx edit 42 --scheduled none
+  cx edit 42 -p p1
+  cx edit 42 --every weekday
+  cx edit 42 --parent 12`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		id, err := strconv.Atoi(args[0])
@@ -194,21 +332,38 @@ Examples:
 			os.Exit(1)
 		}
 
-		fmt.Printf("Current content: %s\n", note.Content)
-		fmt.Print("Enter new content: ")
-		
-		var newContent string
-		fmt.Scanln(&newContent)
-		
-		if newContent == "" {
-			fmt.Println("❌ Content cannot be empty")
-			os.Exit(1)
+		// Flags change only what they name, without prompting
+		parentID, reparented := parentFlag(cmd)
+		if reparented {
+			if err := db.SetParent(id, parentID); err != nil {
+				fmt.Printf("❌ Error moving note: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if applyNoteFlags(cmd, note) {
+			if err := db.SaveNote(note); err != nil {
+				fmt.Printf("❌ Error updating note: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ Note %d updated successfully!\n", id)
+			return
+		}
+		if reparented {
+			fmt.Printf("✅ Note %d updated successfully!\n", id)
+			return
 		}
 
-		// Extract new tags
-		tags := storage.ParseTags(newContent)
+		edited, changed, err := editNote(note)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if !changed {
+			fmt.Printf("📝 No changes to note %d\n", id)
+			return
+		}
 		
-		err = db.UpdateNote(id, newContent, note.Status, tags)
+		err = db.SaveNote(edited)
 		if err != nil {
 			fmt.Printf("❌ Error updating note: %v\n", err)
 			os.Exit(1)
@@ -263,8 +418,27 @@ var listCmd = &cobra.Command{
 	Aliases: []string{"ls", "l"},
 	Short:   "List all notes",
 	Long: `List all notes with their IDs, status, and creation date.
-Useful for finding note IDs for editing or deletion.`,
+Useful for finding note IDs for editing or deletion.
+
+Examples:
+  cx list
+  cx list --over-wip   # Notes in statuses over their WIP limit
+  cx list --overdue    # Notes past their due date and not done
+  cx list --archived   # Notes archived off the board`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if overWIP, _ := cmd.Flags().GetBool("over-wip"); overWIP {
+			listOverWIP()
+			return
+		}
+		if overdue, _ := cmd.Flags().GetBool("overdue"); overdue {
+			listOverdue()
+			return
+		}
+		if archived, _ := cmd.Flags().GetBool("archived"); archived {
+			listArchived()
+			return
+		}
+
 		notes, err := db.GetRecentNotes(50) // Get more notes for listing
 		if err != nil {
 			fmt.Printf("❌ Error fetching notes: %v\n", err)
@@ -280,6 +454,193 @@ Useful for finding note IDs for editing or deletion.`,
 	},
 }
 
+// listOverWIP lists the notes in every status that exceeds its WIP limit
+func listOverWIP() {
+	found := false
+	for _, status := range storage.Statuses {
+		limit := cfg.WIPLimit(status)
+		if limit == 0 {
+			continue
+		}
+
+		notes, err := db.GetNotesByStatus(status, storage.OrderCreated)
+		if err != nil {
+			fmt.Printf("❌ Error fetching notes: %v\n", err)
+			os.Exit(1)
+		}
+		if len(notes) <= limit {
+			continue
+		}
+
+		if found {
+			fmt.Println()
+		}
+		found = true
+		title := fmt.Sprintf("%s over WIP limit (%d/%d)", strings.ToUpper(status), len(notes), limit)
+		fmt.Println(ui.RenderNotesList(notes, title))
+	}
+
+	if !found {
+		fmt.Println("✅ No status is over its WIP limit")
+	}
+}
+
+// listOverdue lists the notes past their due date that are not done
+func listOverdue() {
+	now := time.Now()
+	notes, err := db.GetOverdueNotes(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local))
+	if err != nil {
+		fmt.Printf("❌ Error fetching notes: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(notes) == 0 {
+		fmt.Println("✅ Nothing is overdue")
+		return
+	}
+
+	fmt.Println(ui.RenderNotesList(notes, "Overdue Notes"))
+}
+
+// applyNoteFlags sets the note's dates, priority and recurrence from the
+// --due, --scheduled, --priority and --every flags, reporting whether any
+// was given
+func applyNoteFlags(cmd *cobra.Command, note *storage.Note) bool {
+	changed := false
+	if date, set := dateFlag(cmd, "due"); set {
+		note.DueAt = date
+		changed = true
+	}
+	if date, set := dateFlag(cmd, "scheduled"); set {
+		note.ScheduledAt = date
+		changed = true
+	}
+	if cmd.Flags().Changed("priority") {
+		value, _ := cmd.Flags().GetString("priority")
+		note.Priority = nil
+		if value != "none" {
+			priority, err := storage.ParsePriorityValue(value)
+			if err != nil {
+				fmt.Printf("❌ Invalid --priority: %v\n", err)
+				os.Exit(1)
+			}
+			note.Priority = &priority
+		}
+		changed = true
+	}
+	if cmd.Flags().Changed("every") {
+		value, _ := cmd.Flags().GetString("every")
+		note.Recurrence = ""
+		if value != "none" {
+			if _, err := storage.ParseRecurrence(value); err != nil {
+				fmt.Printf("❌ Invalid --every: %v\n", err)
+				os.Exit(1)
+			}
+			note.Recurrence = value
+		}
+		changed = true
+	}
+	return changed
+}
+
+// readNote returns the note to add from cx add's arguments. It is written
+// in the editor with --editor, or at a terminal when no arguments are
+// given, and read from them or standard input otherwise.
+func readNote(cmd *cobra.Command, args []string) (*storage.Note, error) {
+	useEditor, _ := cmd.Flags().GetBool("editor")
+	if useEditor && len(args) > 0 {
+		return nil, fmt.Errorf("--editor cannot be combined with a note given as arguments")
+	}
+	if useEditor || (len(args) == 0 && stdinIsTerminal()) {
+		note, changed, err := editNote(&storage.Note{Status: "todo"})
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			return nil, fmt.Errorf("aborting: the note was left empty")
+		}
+		return note, nil
+	}
+
+	content, err := readNoteArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tags, dates, priority and recurrence from content
+	note, err := storage.ParseNote(content, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	note.Status = "todo"
+	return note, nil
+}
+
+// readNoteArgs returns the text of a note given as cx add's arguments: a
+// title and an optional body, with - for standard input, or the whole
+// note from standard input when none are given
+func readNoteArgs(args []string) (string, error) {
+	if len(args) == 0 {
+		return readStdin()
+	}
+
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg
+		if arg == "-" {
+			text, err := readStdin()
+			if err != nil {
+				return "", err
+			}
+			parts[i] = text
+		}
+	}
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	return storage.JoinNote(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])), nil
+}
+
+// dateFlag parses a date flag, reporting whether it was given. "none"
+// clears the date, giving nil.
+func dateFlag(cmd *cobra.Command, name string) (*time.Time, bool) {
+	if !cmd.Flags().Changed(name) {
+		return nil, false
+	}
+
+	value, _ := cmd.Flags().GetString(name)
+	if value == "none" {
+		return nil, true
+	}
+
+	date, err := storage.ParseDate(value, time.Now())
+	if err != nil {
+		fmt.Printf("❌ Invalid --%s: %v\n", name, err)
+		os.Exit(1)
+	}
+	return &date, true
+}
+
+// parentFlag parses the --parent flag, a note ID such as 12 or #12,
+// reporting whether it was given. "none" gives 0, for no parent.
+func parentFlag(cmd *cobra.Command) (int, bool) {
+	if !cmd.Flags().Changed("parent") {
+		return 0, false
+	}
+
+	value, _ := cmd.Flags().GetString("parent")
+	if value == "none" {
+		return 0, true
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(value, "#"))
+	if err != nil || id <= 0 {
+		fmt.Printf("❌ Invalid --parent: %s\n", value)
+		os.Exit(1)
+	}
+	return id, true
+}
+
 // searchNotes performs search with fallback from semantic to text search
 func searchNotes(query string) ([]*storage.Note, error) {
 	return search.SearchWithFallback(db, query, 10)
@@ -382,4 +743,26 @@ Currently shows a placeholder message.`,
 func init() {
 	// Add flags for embed command
 	embedCmd.Flags().IntP("note", "n", 0, "Generate embedding for specific note ID")
+
+	// Add flags for list command
+	listCmd.Flags().Bool("over-wip", false, "Only list notes in statuses over their WIP limit")
+	listCmd.Flags().Bool("overdue", false, "Only list notes past their due date that are not done")
+	listCmd.Flags().Bool("archived", false, "Only list archived notes")
+
+	// Add flags for add and edit commands
+	addCmd.Flags().String("due", "", "Due date: today, tomorrow, friday, 3d, 2w, next-week or 2006-01-02")
+	addCmd.Flags().String("scheduled", "", "Scheduled date, in the same forms as --due")
+	addCmd.Flags().StringP("priority", "p", "", "Priority: p0 (most urgent) to p3")
+	addCmd.Flags().String("every", "", "Recurrence: daily, weekday, 2w, mon,thu or an RRULE")
+	addCmd.Flags().String("parent", "", "ID of the note this one is a subtask of")
+	addCmd.Flags().BoolP("editor", "e", false, "Write the note in $VISUAL or $EDITOR")
+	editCmd.Flags().String("due", "", "New due date, as for cx add, or none to clear it")
+	editCmd.Flags().String("scheduled", "", "New scheduled date, as for cx add, or none to clear it")
+	editCmd.Flags().StringP("priority", "p", "", "New priority, p0 to p3, or none to clear it")
+	editCmd.Flags().String("every", "", "New recurrence, as for cx add, or none to stop it")
+	editCmd.Flags().String("parent", "", "ID of the new parent note, or none to move it to the top level")
+
+	// Add flags for kanban command
+	kanbanCmd.Flags().String("swimlanes", "", "Group cards into swimlanes: tag, prefix:<prefix>, priority or assignee")
+	kanbanCmd.Flags().String("sort", storage.OrderCreated, "Card order: created or priority")
 }
\ No newline at end of file
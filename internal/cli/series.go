@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/ui"
+)
+
+// seriesCmd represents the series command
+var seriesCmd = &cobra.Command{
+	Use:   "series <id>",
+	Short: "List every occurrence of a recurring note",
+	Long: `List every occurrence of a recurring note, oldest first: the note that
+started the series and each one added when the one before it was done.
+Any note in the series can be given.
+
+Examples:
+  cx series 42
+  cx series #42`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			fmt.Printf("❌ Invalid note ID: %s\n", args[0])
+			os.Exit(1)
+		}
+
+		note, err := db.GetNote(id)
+		if err != nil {
+			fmt.Printf("❌ Error fetching note: %v\n", err)
+			os.Exit(1)
+		}
+		if note.SeriesID == 0 && note.Recurrence == "" {
+			fmt.Printf("🔁 #%d does not recur\n", id)
+			return
+		}
+
+		seriesID := note.SeriesID
+		if seriesID == 0 {
+			seriesID = note.ID
+		}
+		notes, err := db.GetSeries(seriesID)
+		if err != nil {
+			fmt.Printf("❌ Error fetching notes: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(ui.RenderNotesList(notes, fmt.Sprintf("Series started by #%d", seriesID)))
+	},
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/stats"
+	"cheesebox/internal/storage"
+	"cheesebox/internal/ui"
+)
+
+// statsCmd groups the statistics subcommands
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about your notes",
+	Long: `Show a quick health check of your backlog:
+
+• Notes in each status, with archived notes counted apart
+• Notes created and completed on each of the last days, as sparklines
+• The most used tags and the oldest open notes
+• How many notes have embeddings for semantic search, and the database size
+
+Use --json for machine-readable output, and cx stats flow for lead time,
+cycle time and cumulative flow.
+
+Examples:
+  cx stats
+  cx stats --days 30
+  cx stats --json
+  cx stats flow`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		days, _ := cmd.Flags().GetInt("days")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		if days < 1 {
+			fmt.Printf("❌ Invalid --days: %d (must be at least 1)\n", days)
+			os.Exit(1)
+		}
+
+		notes, err := db.GetAllNotes()
+		if err != nil {
+			fmt.Printf("❌ Error fetching notes: %v\n", err)
+			os.Exit(1)
+		}
+		events, err := db.GetStatusEvents()
+		if err != nil {
+			fmt.Printf("❌ Error fetching status changes: %v\n", err)
+			os.Exit(1)
+		}
+		embedded, err := db.CountEmbeddings()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		size, err := db.DatabaseSize()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		overview := stats.ComputeOverview(notes, events, embedded, size, days, time.Now())
+		if asJSON {
+			data, err := json.MarshalIndent(overview, "", "  ")
+			if err != nil {
+				fmt.Printf("❌ Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Println(ui.RenderOverview(overview))
+	},
+}
+
+// statsFlowCmd represents the stats flow command
+var statsFlowCmd = &cobra.Command{
+	Use:   "flow",
+	Short: "Show lead time, cycle time, throughput and cumulative flow",
+	Long: `Show how work flows across the board, from the log of every status
+change:
+
+• Lead time: from a note's creation to its completion
+• Cycle time: from when it first entered doing to its completion
+• Throughput: notes completed each week
+• Aging work in progress: how long each note in doing has been there,
+  in red once older than 85% of completed cycle times
+• A cumulative flow diagram of the notes in each status per day
+
+Notes count as completed when they last moved into done during the
+period and are still done. --since takes an offset back from today such
+as 2w or 3m, a weekday, or a date like 2006-01-02.
+
+Examples:
+  cx stats flow
+  cx stats flow --since 12w`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceValue, _ := cmd.Flags().GetString("since")
+
+		now := time.Now()
+		since, err := storage.ParseSince(sinceValue, now)
+		if err != nil {
+			fmt.Printf("❌ Invalid --since: %v\n", err)
+			os.Exit(1)
+		}
+
+		notes, err := db.GetAllNotes()
+		if err != nil {
+			fmt.Printf("❌ Error fetching notes: %v\n", err)
+			os.Exit(1)
+		}
+		events, err := db.GetStatusEvents()
+		if err != nil {
+			fmt.Printf("❌ Error fetching status changes: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(ui.RenderFlow(stats.ComputeFlow(notes, events, since, now)))
+	},
+}
+
+func init() {
+	statsCmd.AddCommand(statsFlowCmd)
+
+	statsCmd.Flags().Int("days", 14, "Number of days of activity to show")
+	statsCmd.Flags().Bool("json", false, "Output as JSON")
+
+	statsFlowCmd.Flags().String("since", "4w", "Start of the period: an offset like 4w, a weekday or a date")
+}
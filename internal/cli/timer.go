@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/storage"
+	"cheesebox/internal/ui"
+)
+
+// Groupings for cx report time
+const (
+	reportByNote = "note"
+	reportByTag  = "tag"
+	reportByDay  = "day"
+)
+
+// startCmd represents the start command for note timers
+var startCmd = &cobra.Command{
+	Use:   "start [id]",
+	Short: "Start timing work on a note",
+	Long: `Start the timer on a note, recording a work session until cx stop.
+Only one timer runs at a time: starting another stops the current one.
+
+Set "auto_timer": true in ~/.cheesebox/config.json to start a note's
+timer whenever it moves into doing, and stop it when it leaves. On the
+kanban board, T starts or stops the timer on the selected card.
+
+Examples:
+  cx start 42
+  cx stop`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Printf("❌ Invalid note ID: %s\n", args[0])
+			os.Exit(1)
+		}
+
+		note, err := db.GetNote(id)
+		if err != nil {
+			fmt.Printf("❌ Error fetching note: %v\n", err)
+			os.Exit(1)
+		}
+
+		active, err := db.ActiveTimer()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if active != nil && active.NoteID == id {
+			fmt.Printf("⏱️  Already timing #%d (%s so far)\n", id, ui.FormatTracked(active.Duration(time.Now())))
+			return
+		}
+
+		stopped, err := db.StartTimer(id)
+		if err != nil {
+			fmt.Printf("❌ Error starting timer: %v\n", err)
+			os.Exit(1)
+		}
+		if stopped != nil {
+			fmt.Printf("⏹️  Stopped #%d after %s\n", stopped.NoteID, ui.FormatTracked(stopped.Duration(time.Now())))
+		}
+		fmt.Printf("✅ Timing #%d: %s\n", id, note.Title)
+	},
+}
+
+// stopCmd represents the stop command for note timers
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Long: `Stop the running timer, ending its work session.
+
+Examples:
+  cx stop`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		stopped, err := db.StopTimer()
+		if err != nil {
+			fmt.Printf("❌ Error stopping timer: %v\n", err)
+			os.Exit(1)
+		}
+		if stopped == nil {
+			fmt.Println("⏱️  No timer is running")
+			return
+		}
+		fmt.Printf("✅ Stopped #%d after %s\n", stopped.NoteID, ui.FormatTracked(stopped.Duration(time.Now())))
+	},
+}
+
+// reportCmd groups the report subcommands
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show reports on your notes",
+	Long: `Show reports on your notes.
+
+Examples:
+  cx report time --since 2w --by tag`,
+}
+
+// reportTimeCmd represents the report time command
+var reportTimeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Show time tracked with note timers",
+	Long: `Show the time tracked with note timers since a day, grouped by note,
+tag or day. --since takes an offset back from today such as 3d, 2w or
+1m, a weekday for its most recent day, or a date like 2006-01-02.
+Sessions still running count up to now. A note with several tags counts
+toward each of them.
+
+Examples:
+  cx report time
+  cx report time --since 2w --by tag
+  cx report time --since monday --by day`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceValue, _ := cmd.Flags().GetString("since")
+		by, _ := cmd.Flags().GetString("by")
+
+		now := time.Now()
+		since, err := storage.ParseSince(sinceValue, now)
+		if err != nil {
+			fmt.Printf("❌ Invalid --since: %v\n", err)
+			os.Exit(1)
+		}
+		if by != reportByNote && by != reportByTag && by != reportByDay {
+			fmt.Printf("❌ Invalid --by: %s (use %s, %s or %s)\n", by, reportByNote, reportByTag, reportByDay)
+			os.Exit(1)
+		}
+
+		entries, err := db.GetTimeEntries(since)
+		if err != nil {
+			fmt.Printf("❌ Error fetching time entries: %v\n", err)
+			os.Exit(1)
+		}
+
+		notes, err := db.GetTimeEntryNotes(since)
+		if err != nil {
+			fmt.Printf("❌ Error fetching notes: %v\n", err)
+			os.Exit(1)
+		}
+
+		rows, total := groupTimeEntries(entries, notes, since, now, by)
+		title := fmt.Sprintf("⏱️  Time tracked since %s, by %s", since.Format("Mon Jan 2"), by)
+		fmt.Println(ui.RenderTimeReport(title, rows, total))
+	},
+}
+
+// groupTimeEntries totals the time in entries between since and now for
+// each group, largest first, or in date order when grouping by day, with
+// the entries' notes looked up in notes. It also returns the overall
+// total.
+func groupTimeEntries(entries []*storage.TimeEntry, notes map[int]*storage.Note, since, now time.Time, by string) ([]ui.TimeReportRow, time.Duration) {
+	totals := make(map[string]time.Duration)
+	days := make(map[string]time.Time)
+	var total time.Duration
+
+	for _, entry := range entries {
+		start, end := entry.StartedAt, now
+		if entry.EndedAt != nil {
+			end = *entry.EndedAt
+		}
+		if start.Before(since) {
+			start = since
+		}
+		if !end.After(start) {
+			continue
+		}
+		total += end.Sub(start)
+
+		// Deleted notes keep their entries but have no note to label
+		note := notes[entry.NoteID]
+
+		switch by {
+		case reportByDay:
+			// Split sessions that run past midnight between their days
+			for start.Before(end) {
+				day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+				next := day.AddDate(0, 0, 1)
+				if next.After(end) {
+					next = end
+				}
+				label := day.Format("Mon Jan 2")
+				totals[label] += next.Sub(start)
+				days[label] = day
+				start = next
+			}
+		case reportByTag:
+			switch {
+			case note == nil:
+				totals["(deleted notes)"] += end.Sub(start)
+			case len(note.Tags) == 0:
+				totals["(untagged)"] += end.Sub(start)
+			}
+			if note != nil {
+				for _, tag := range note.Tags {
+					totals["#"+tag] += end.Sub(start)
+				}
+			}
+		default:
+			label := fmt.Sprintf("#%d (deleted)", entry.NoteID)
+			if note != nil {
+				label = fmt.Sprintf("#%d %s", note.ID, note.Title)
+			}
+			totals[label] += end.Sub(start)
+		}
+	}
+
+	rows := make([]ui.TimeReportRow, 0, len(totals))
+	for label, duration := range totals {
+		rows = append(rows, ui.TimeReportRow{Label: label, Duration: duration})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if by == reportByDay {
+			return days[rows[i].Label].Before(days[rows[j].Label])
+		}
+		if rows[i].Duration != rows[j].Duration {
+			return rows[i].Duration > rows[j].Duration
+		}
+		return rows[i].Label < rows[j].Label
+	})
+	return rows, total
+}
+
+func init() {
+	reportCmd.AddCommand(reportTimeCmd)
+
+	reportTimeCmd.Flags().String("since", "1w", "Start of the report: an offset like 2w, a weekday or a date")
+	reportTimeCmd.Flags().String("by", reportByNote, "Group by note, tag or day")
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/storage"
+	"cheesebox/internal/ui"
+)
+
+// treeCmd represents the tree command
+var treeCmd = &cobra.Command{
+	Use:   "tree [id]",
+	Short: "Show notes with their subtasks as a tree",
+	Long: `Show parent notes, such as epics, with their subtasks at every depth.
+Each parent shows its rolled-up progress: how many of the notes below
+it are done. Given an ID, only that note and the notes below it are
+shown.
+
+Create subtasks with cx add --parent, and move a note with
+cx edit --parent.
+
+Examples:
+  cx tree
+  cx tree 12`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		progress, err := db.GetProgress()
+		if err != nil {
+			fmt.Printf("❌ Error computing progress: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) == 1 {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Printf("❌ Invalid note ID: %s\n", args[0])
+				os.Exit(1)
+			}
+			notes, err := db.GetSubtree(id)
+			if err != nil {
+				fmt.Printf("❌ Error fetching notes: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(ui.RenderTree(notes, progress, fmt.Sprintf("Subtasks of #%d", id)))
+			return
+		}
+
+		all, err := db.GetAllNotes()
+		if err != nil {
+			fmt.Printf("❌ Error fetching notes: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Only notes with a parent or subtasks belong to a tree
+		var notes []*storage.Note
+		for _, note := range all {
+			if note.ParentID != 0 || progress[note.ID].Total > 0 {
+				notes = append(notes, note)
+			}
+		}
+		fmt.Println(ui.RenderTree(notes, progress, "Note tree"))
+	},
+}
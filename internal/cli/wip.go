@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"cheesebox/internal/config"
+	"cheesebox/internal/storage"
+)
+
+// wipCmd represents the wip command for viewing and setting WIP limits
+var wipCmd = &cobra.Command{
+	Use:   "wip [status] [limit]",
+	Short: "Show or set per-status WIP limits",
+	Long: `Show the work-in-progress limit for each status, or set one.
+A limit of 0 removes it. Limits are stored in ~/.cheesebox/config.json.
+
+The kanban board shows counts against limits and asks before a move would
+exceed one. Set "wip_enforcement" to "block" in the config to refuse such
+moves instead.
+
+Examples:
+  cx wip            # Show limits and current counts
+  cx wip doing 3    # Allow at most 3 notes in doing
+  cx wip doing 0    # Remove the limit`,
+	Args: cobra.RangeArgs(0, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			fmt.Println("❌ Provide both a status and a limit, e.g. cx wip doing 3")
+			os.Exit(1)
+		}
+
+		if len(args) == 2 {
+			status := strings.ToLower(args[0])
+			if !storage.IsValidStatus(status) {
+				fmt.Printf("❌ Invalid status: %s (use %s)\n", args[0], strings.Join(storage.Statuses, ", "))
+				os.Exit(1)
+			}
+
+			limit, err := strconv.Atoi(args[1])
+			if err != nil || limit < 0 {
+				fmt.Printf("❌ Invalid limit: %s\n", args[1])
+				os.Exit(1)
+			}
+
+			if limit == 0 {
+				delete(cfg.WIPLimits, status)
+			} else {
+				cfg.WIPLimits[status] = limit
+			}
+			if err := cfg.Save(); err != nil {
+				fmt.Printf("❌ Error saving config: %v\n", err)
+				os.Exit(1)
+			}
+
+			if limit == 0 {
+				fmt.Printf("✅ Removed the WIP limit on %s\n", status)
+			} else {
+				fmt.Printf("✅ WIP limit on %s set to %d\n", status, limit)
+			}
+			return
+		}
+
+		fmt.Println("🚦 WIP Limits")
+		for _, status := range storage.Statuses {
+			notes, err := db.GetNotesByStatus(status, storage.OrderCreated)
+			if err != nil {
+				fmt.Printf("❌ Error fetching notes: %v\n", err)
+				os.Exit(1)
+			}
+
+			limit := cfg.WIPLimit(status)
+			switch {
+			case limit == 0:
+				fmt.Printf("   %-6s %d (no limit)\n", status, len(notes))
+			case len(notes) > limit:
+				fmt.Printf("⚠️  %-6s %d/%d over limit\n", status, len(notes), limit)
+			default:
+				fmt.Printf("   %-6s %d/%d\n", status, len(notes), limit)
+			}
+		}
+		if cfg.WIPEnforcement == config.WIPBlock {
+			fmt.Println("\nMoves past a limit are blocked on the kanban board.")
+		}
+	},
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WIP enforcement modes
+const (
+	WIPConfirm = "confirm" // Ask before a move exceeds a limit (default)
+	WIPBlock   = "block"   // Refuse moves that exceed a limit
+)
+
+// Config holds user settings read from ~/.cheesebox/config.json
+type Config struct {
+	// WIPLimits maps a status to the most notes it should hold. Statuses
+	// without a limit, or with a limit of 0, are unlimited.
+	WIPLimits map[string]int `json:"wip_limits,omitempty"`
+
+	// WIPEnforcement is WIPConfirm or WIPBlock
+	WIPEnforcement string `json:"wip_enforcement,omitempty"`
+
+	// Keys overrides kanban key bindings: each action name maps to the
+	// keys that trigger it, e.g. {"up": ["k", "ctrl+p"]}
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// Theme names the colour theme: a built-in theme or one of Themes
+	Theme string `json:"theme,omitempty"`
+
+	// Themes defines custom themes by name
+	Themes map[string]Theme `json:"themes,omitempty"`
+
+	// AutoTimer starts a note's timer when it moves into doing and stops
+	// it when the note leaves
+	AutoTimer bool `json:"auto_timer,omitempty"`
+
+	// AutoDone moves a note to done when the last open item of its
+	// checklist is checked
+	AutoDone bool `json:"auto_done,omitempty"`
+}
+
+// Theme is a custom colour theme: a built-in base theme with some of its
+// colours replaced. Colors maps a colour name such as "accent" or "text"
+// to its value.
+type Theme struct {
+	Base   string           `json:"base,omitempty"`
+	Colors map[string]Color `json:"colors"`
+}
+
+// Color is a theme colour: a hex code or ANSI number, or a pair of them
+// for light and dark terminal backgrounds. In JSON it is written as
+// "#ff6b6b" or {"light": "#2c3e50", "dark": "#eceff1"}.
+type Color struct {
+	Light string `json:"light"`
+	Dark  string `json:"dark"`
+}
+
+// UnmarshalJSON accepts a single colour string or a light/dark pair
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		c.Light, c.Dark = single, single
+		return nil
+	}
+
+	type pair Color
+	var p pair
+	if err := json.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("colour must be a string or {\"light\": ..., \"dark\": ...}: %w", err)
+	}
+	if p.Light == "" || p.Dark == "" {
+		return fmt.Errorf("colour pair needs both light and dark")
+	}
+	*c = Color(p)
+	return nil
+}
+
+// MarshalJSON writes a single string when both values are the same
+func (c Color) MarshalJSON() ([]byte, error) {
+	if c.Light == c.Dark {
+		return json.Marshal(c.Light)
+	}
+	type pair Color
+	return json.Marshal(pair(c))
+}
+
+// Load reads the config file, returning defaults when it doesn't exist
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg.withDefaults(), nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	switch cfg.WIPEnforcement {
+	case "", WIPConfirm, WIPBlock:
+	default:
+		return nil, fmt.Errorf("invalid wip_enforcement %q in %s (use %q or %q)", cfg.WIPEnforcement, path, WIPConfirm, WIPBlock)
+	}
+
+	return cfg.withDefaults(), nil
+}
+
+// Save writes the config file
+func (c *Config) Save() error {
+	path, err := Path()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return nil
+}
+
+// WIPLimit returns the WIP limit for a status, or 0 when it is unlimited
+func (c *Config) WIPLimit(status string) int {
+	return c.WIPLimits[status]
+}
+
+// withDefaults fills in unset fields
+func (c *Config) withDefaults() *Config {
+	if c.WIPLimits == nil {
+		c.WIPLimits = make(map[string]int)
+	}
+	if c.WIPEnforcement == "" {
+		c.WIPEnforcement = WIPConfirm
+	}
+	return c
+}
+
+// Path returns the path to the config file
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".cheesebox", "config.json"), nil
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"sort"
 	"time"
 	
 	"cheesebox/internal/storage"
@@ -172,7 +173,7 @@ func (c *OllamaClient) GenerateEmbeddingsForAllNotes(storage *storage.Storage) e
 		}
 
 		// Generate embedding
-		embedding, err := c.GetEmbedding(note.Content)
+		embedding, err := c.GetEmbedding(note.Text())
 		if err != nil {
 			fmt.Printf("Failed to generate embedding for note %d: %v\n", note.ID, err)
 			errorCount++
@@ -204,7 +205,7 @@ func (c *OllamaClient) GenerateEmbeddingForNote(storage *storage.Storage, noteID
 		return fmt.Errorf("failed to get note: %w", err)
 	}
 
-	embedding, err := c.GetEmbedding(note.Content)
+	embedding, err := c.GetEmbedding(note.Text())
 	if err != nil {
 		return fmt.Errorf("failed to generate embedding: %w", err)
 	}
@@ -256,4 +257,67 @@ func SearchWithFallback(s *storage.Storage, query string, limit int) ([]*storage
 
 	// Fallback to text search
 	return s.SearchNotes(query)
-}
\ No newline at end of file
+}
+
+// RelatedNotes finds the notes most similar to note. Notes are compared by
+// their stored embeddings when note has one, and otherwise ranked by how
+// many tags they share with it. Ollama doesn't need to be running.
+func RelatedNotes(s *storage.Storage, note *storage.Note, limit int) ([]*storage.Note, error) {
+	notes, err := s.GetNotesWithEmbeddings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	var embedding []float64
+	for _, candidate := range notes {
+		if candidate.ID == note.ID {
+			embedding = candidate.Embedding
+		}
+	}
+
+	var results []*SearchResult
+	if len(embedding) > 0 {
+		for _, candidate := range notes {
+			if candidate.ID == note.ID || len(candidate.Embedding) == 0 {
+				continue
+			}
+			if similarity := cosineSimilarity(embedding, candidate.Embedding); similarity > 0.5 {
+				results = append(results, &SearchResult{Note: candidate, Similarity: similarity})
+			}
+		}
+	} else {
+		all, err := s.GetAllNotes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get notes: %w", err)
+		}
+
+		tags := make(map[string]bool)
+		for _, tag := range note.Tags {
+			tags[tag] = true
+		}
+		for _, candidate := range all {
+			shared := 0
+			for _, tag := range candidate.Tags {
+				if tags[tag] {
+					shared++
+				}
+			}
+			if candidate.ID != note.ID && shared > 0 {
+				results = append(results, &SearchResult{Note: candidate, Similarity: float64(shared)})
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Similarity > results[j].Similarity
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	related := make([]*storage.Note, len(results))
+	for i, result := range results {
+		related[i] = result.Note
+	}
+	return related, nil
+}
@@ -0,0 +1,212 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"cheesebox/internal/storage"
+)
+
+// Flow holds flow metrics for the notes completed in a period and the
+// work in progress at its end
+type Flow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// LeadTime runs from a note's creation to its completion, CycleTime
+	// from when it first entered doing to its completion. Notes that were
+	// never in doing have no cycle time.
+	LeadTime  Summary `json:"lead_time"`
+	CycleTime Summary `json:"cycle_time"`
+
+	// Throughput counts the notes completed in each week of the period
+	Throughput []WeekCount `json:"throughput"`
+
+	// Aging lists the notes now in doing, oldest first
+	Aging []AgingItem `json:"aging"`
+
+	// CFD counts the notes in each status at the end of every day of the
+	// period, for a cumulative flow diagram
+	CFD []DayCounts `json:"cfd"`
+}
+
+// Summary describes a set of durations
+type Summary struct {
+	Count  int           `json:"count"`
+	Median time.Duration `json:"median"`
+	Mean   time.Duration `json:"mean"`
+	P85    time.Duration `json:"p85"` // 85% of the durations are at most this
+}
+
+// WeekCount is a count for the week starting on Start, a Monday
+type WeekCount struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// AgingItem is a note in progress and how long it has been there
+type AgingItem struct {
+	Note  *storage.Note `json:"note"`
+	Since time.Time     `json:"since"`
+	Age   time.Duration `json:"age"`
+}
+
+// DayCounts maps each status to the number of notes in it at the end of
+// Day
+type DayCounts struct {
+	Day    time.Time      `json:"day"`
+	Counts map[string]int `json:"counts"`
+}
+
+// ComputeFlow computes flow metrics from notes and their status events
+// for the period from from to now. A note counts as completed when its
+// latest move into done falls in the period and it is still done. A note
+// without events is taken to have been in its status since it last
+// changed.
+func ComputeFlow(notes []*storage.Note, events []*storage.StatusEvent, from, now time.Time) *Flow {
+	from = StartOfDay(from)
+	flow := &Flow{From: from, To: now}
+
+	byNote := make(map[int][]*storage.StatusEvent)
+	for _, event := range events {
+		byNote[event.NoteID] = append(byNote[event.NoteID], event)
+	}
+
+	var leadTimes, cycleTimes []time.Duration
+	completed := make(map[time.Time]int)
+	for _, note := range notes {
+		history := noteHistory(note, byNote)
+
+		switch note.Status {
+		case "done":
+			doneAt, ok := lastEntry(history, "done")
+			if !ok || doneAt.Before(from) {
+				continue
+			}
+			leadTimes = append(leadTimes, doneAt.Sub(note.CreatedAt))
+			if startedAt, ok := firstEntry(history, "doing"); ok && startedAt.Before(doneAt) {
+				cycleTimes = append(cycleTimes, doneAt.Sub(startedAt))
+			}
+			completed[StartOfWeek(doneAt)]++
+
+		case "doing":
+			if note.ArchivedAt != nil {
+				continue
+			}
+			since, ok := lastEntry(history, "doing")
+			if !ok {
+				since = note.StatusChangedAt
+			}
+			flow.Aging = append(flow.Aging, AgingItem{Note: note, Since: since, Age: now.Sub(since)})
+		}
+	}
+
+	flow.LeadTime = Summarize(leadTimes)
+	flow.CycleTime = Summarize(cycleTimes)
+
+	for week := StartOfWeek(from); !week.After(now); week = week.AddDate(0, 0, 7) {
+		flow.Throughput = append(flow.Throughput, WeekCount{Start: week, Count: completed[week]})
+	}
+
+	sort.Slice(flow.Aging, func(i, j int) bool {
+		return flow.Aging[i].Since.Before(flow.Aging[j].Since)
+	})
+
+	for day := from; !day.After(now); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		counts := make(map[string]int)
+		for _, note := range notes {
+			if status := statusAt(noteHistory(note, byNote), end); status != "" {
+				counts[status]++
+			}
+		}
+		flow.CFD = append(flow.CFD, DayCounts{Day: day, Counts: counts})
+	}
+
+	return flow
+}
+
+// Summarize computes the median, mean and 85th percentile of durations
+func Summarize(durations []time.Duration) Summary {
+	if len(durations) == 0 {
+		return Summary{}
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	// Nearest-rank percentile
+	rank := (85*len(sorted) + 99) / 100
+	return Summary{
+		Count:  len(sorted),
+		Median: median,
+		Mean:   total / time.Duration(len(sorted)),
+		P85:    sorted[rank-1],
+	}
+}
+
+// noteHistory returns a note's status events in order, or a single event
+// for entering its status when it last changed if it has none
+func noteHistory(note *storage.Note, byNote map[int][]*storage.StatusEvent) []*storage.StatusEvent {
+	if history := byNote[note.ID]; len(history) > 0 {
+		return history
+	}
+	return []*storage.StatusEvent{{NoteID: note.ID, To: note.Status, At: note.StatusChangedAt}}
+}
+
+// firstEntry returns when a note, given its events in order, first moved
+// into status
+func firstEntry(history []*storage.StatusEvent, status string) (time.Time, bool) {
+	for _, event := range history {
+		if event.To == status {
+			return event.At, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// lastEntry returns when a note, given its events in order, last moved
+// into status
+func lastEntry(history []*storage.StatusEvent, status string) (time.Time, bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].To == status {
+			return history[i].At, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// statusAt returns the status a note, given its events in order, had just
+// before t, or "" if it did not exist yet
+func statusAt(history []*storage.StatusEvent, t time.Time) string {
+	status := ""
+	for _, event := range history {
+		if !event.At.Before(t) {
+			break
+		}
+		status = event.To
+	}
+	return status
+}
+
+// StartOfDay returns local midnight at the start of t's day
+func StartOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// StartOfWeek returns local midnight on the Monday of t's week
+func StartOfWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
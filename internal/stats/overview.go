@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"cheesebox/internal/storage"
+)
+
+// Sizes of the lists in an overview
+const (
+	overviewTopTags = 5
+	overviewOldest  = 5
+)
+
+// Overview is a health check of the backlog: what is in each status,
+// recent activity, the busiest tags, the oldest open work and the state
+// of the search index
+type Overview struct {
+	Total int `json:"total"`
+
+	// ByStatus counts the notes on the board in each status. Archived
+	// notes are counted apart.
+	ByStatus map[string]int `json:"by_status"`
+	Archived int            `json:"archived"`
+
+	// Activity counts the notes created and completed on each of the
+	// last days, oldest first
+	Activity []DayActivity `json:"activity"`
+
+	TopTags []TagCount      `json:"top_tags"`
+	Oldest  []*storage.Note `json:"oldest_open"`
+
+	// Embedded counts the notes with vectors for semantic search and
+	// Coverage is their share of all notes, from 0 to 1
+	Embedded int     `json:"embedded"`
+	Coverage float64 `json:"embedding_coverage"`
+
+	DatabaseSize int64 `json:"db_size_bytes"`
+}
+
+// DayActivity counts the notes created and completed on Day
+type DayActivity struct {
+	Day       time.Time `json:"day"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+// TagCount is a tag and the number of notes carrying it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// ComputeOverview builds an overview of notes with activity over the last
+// days up to now. A note counts as completed on each day it moved into
+// done. embedded and databaseSize come from storage as they are.
+func ComputeOverview(notes []*storage.Note, events []*storage.StatusEvent, embedded int, databaseSize int64, days int, now time.Time) *Overview {
+	overview := &Overview{
+		Total:        len(notes),
+		ByStatus:     make(map[string]int),
+		TopTags:      []TagCount{},
+		Embedded:     embedded,
+		DatabaseSize: databaseSize,
+	}
+	for _, status := range storage.Statuses {
+		overview.ByStatus[status] = 0
+	}
+	if len(notes) > 0 {
+		overview.Coverage = float64(embedded) / float64(len(notes))
+	}
+
+	from := StartOfDay(now).AddDate(0, 0, -(days - 1))
+	overview.Activity = make([]DayActivity, days)
+	for i := range overview.Activity {
+		overview.Activity[i].Day = from.AddDate(0, 0, i)
+	}
+	// dayIndex returns the index of t's day in Activity, or -1 outside it
+	dayIndex := func(t time.Time) int {
+		day := StartOfDay(t)
+		if day.Before(from) {
+			return -1
+		}
+		i := int(day.Sub(from).Hours()+12) / 24
+		if i >= days {
+			return -1
+		}
+		return i
+	}
+
+	tags := make(map[string]int)
+	open := []*storage.Note{}
+	for _, note := range notes {
+		if i := dayIndex(note.CreatedAt); i >= 0 {
+			overview.Activity[i].Created++
+		}
+		for _, tag := range note.Tags {
+			tags[tag]++
+		}
+
+		if note.ArchivedAt != nil {
+			overview.Archived++
+			continue
+		}
+		overview.ByStatus[note.Status]++
+		if note.Status != "done" {
+			open = append(open, note)
+		}
+	}
+
+	for _, event := range events {
+		if event.To != "done" {
+			continue
+		}
+		if i := dayIndex(event.At); i >= 0 {
+			overview.Activity[i].Completed++
+		}
+	}
+
+	for tag, count := range tags {
+		overview.TopTags = append(overview.TopTags, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(overview.TopTags, func(i, j int) bool {
+		if overview.TopTags[i].Count != overview.TopTags[j].Count {
+			return overview.TopTags[i].Count > overview.TopTags[j].Count
+		}
+		return overview.TopTags[i].Tag < overview.TopTags[j].Tag
+	})
+	if len(overview.TopTags) > overviewTopTags {
+		overview.TopTags = overview.TopTags[:overviewTopTags]
+	}
+
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].CreatedAt.Before(open[j].CreatedAt)
+	})
+	if len(open) > overviewOldest {
+		open = open[:overviewOldest]
+	}
+	overview.Oldest = open
+
+	return overview
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// checklistPattern matches a markdown checkbox line such as "- [x] item",
+// capturing the text before the mark, the mark and the rest of the line
+var checklistPattern = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\]\s+.*)$`)
+
+// ChecklistItem is a markdown checkbox in a note's content
+type ChecklistItem struct {
+	Text    string
+	Checked bool
+
+	line int // Index of the item's line in the content
+}
+
+// ParseChecklist returns the checkbox items in content, in order. Items
+// inside fenced code blocks are not part of the checklist.
+func ParseChecklist(content string) []ChecklistItem {
+	var items []ChecklistItem
+	inCode := false
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if match := checklistPattern.FindStringSubmatch(line); match != nil {
+			items = append(items, ChecklistItem{
+				Text:    strings.TrimSpace(strings.TrimPrefix(match[3], "]")),
+				Checked: match[2] != " ",
+				line:    i,
+			})
+		}
+	}
+	return items
+}
+
+// ChecklistProgress returns how many of the checkbox items in content are
+// checked, and how many there are
+func ChecklistProgress(content string) (checked, total int) {
+	for _, item := range ParseChecklist(content) {
+		if item.Checked {
+			checked++
+		}
+		total++
+	}
+	return checked, total
+}
+
+// ToggleChecklistItem returns content with its nth checkbox item, counting
+// from 1, checked or unchecked
+func ToggleChecklistItem(content string, n int) (string, error) {
+	items := ParseChecklist(content)
+	if len(items) == 0 {
+		return "", fmt.Errorf("no checklist items")
+	}
+	if n < 1 || n > len(items) {
+		return "", fmt.Errorf("no checklist item %d (items run from 1 to %d)", n, len(items))
+	}
+
+	lines := strings.Split(content, "\n")
+	item := items[n-1]
+	mark := "x"
+	if item.Checked {
+		mark = " "
+	}
+	lines[item.line] = checklistPattern.ReplaceAllString(lines[item.line], "${1}"+mark+"${3}")
+	return strings.Join(lines, "\n"), nil
+}
+
+// ResetChecklist returns content with every checkbox item unchecked
+func ResetChecklist(content string) string {
+	items := ParseChecklist(content)
+	if len(items) == 0 {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	for _, item := range items {
+		lines[item.line] = checklistPattern.ReplaceAllString(lines[item.line], "${1} ${3}")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetAutoDone turns automatic completion on or off. When on, checking
+// the last open item of a note's checklist moves the note to done.
+func (s *Storage) SetAutoDone(enabled bool) {
+	s.autoDone = enabled
+}
+
+// CheckItem checks or unchecks the nth checklist item of a note, counting
+// from 1, and returns the updated note. With automatic completion on, a
+// note whose items are now all checked moves to done.
+func (s *Storage) CheckItem(id, n int) (*Note, error) {
+	err := s.withTx(func(tx *sql.Tx) error {
+		var content string
+		err := tx.QueryRow(`SELECT content FROM notes WHERE id = ?`, id).Scan(&content)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("note with ID %d not found", id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read note: %w", err)
+		}
+
+		content, err = ToggleChecklistItem(content, n)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if _, err := tx.Exec(`UPDATE notes SET content = ?, updated_at = ? WHERE id = ?`, content, now, id); err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+
+		if checked, total := ChecklistProgress(content); s.autoDone && checked == total {
+			return s.setStatus(tx, id, "done", now)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetNote(id)
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// dateTokenPattern matches date tokens written in note content:
+// due:<date>, scheduled:<date> and @<date>. Only those starting a word
+// count (see tokenStart).
+var dateTokenPattern = regexp.MustCompile(`(due:|scheduled:|@)(\S+)`)
+
+// ParseDates extracts the due and scheduled dates written in content, in
+// the same spirit as ParseTags: due:<date> sets the due date, and
+// @<date> or scheduled:<date> the scheduled date. Dates are resolved
+// against now, so the tokens are removed from the returned content
+// rather than left to go stale. Words like @alice that are not dates are
+// kept; an invalid due: or scheduled: date is an error.
+func ParseDates(content string, now time.Time) (string, *time.Time, *time.Time, error) {
+	var dueAt, scheduledAt *time.Time
+	var tokens [][2]int
+
+	for _, match := range dateTokenPattern.FindAllStringSubmatchIndex(content, -1) {
+		if !tokenStart(content, match[0]) {
+			continue
+		}
+		prefix := content[match[2]:match[3]]
+		value := strings.TrimRight(content[match[4]:match[5]], ".,!?;")
+
+		date, err := ParseDate(value, now)
+		if err != nil {
+			if prefix == "@" {
+				continue
+			}
+			return "", nil, nil, fmt.Errorf("invalid date in %s%s: %w", prefix, value, err)
+		}
+
+		if prefix == "due:" {
+			dueAt = &date
+		} else {
+			scheduledAt = &date
+		}
+		tokens = append(tokens, [2]int{match[0], match[4] + len(value)})
+	}
+
+	return cutTokens(content, tokens), dueAt, scheduledAt, nil
+}
+
+// tokenStart reports whether a token found at i in content starts a word:
+// it is at the start of content or follows whitespace
+func tokenStart(content string, i int) bool {
+	return i == 0 || unicode.IsSpace(rune(content[i-1]))
+}
+
+// cutTokens returns content, trimmed, without the tokens at the given
+// spans, in order. Each goes with the spaces after it, or those before it
+// when none follow, so the lines around a token are never joined.
+func cutTokens(content string, tokens [][2]int) string {
+	var rest strings.Builder
+	last := 0
+	for _, token := range tokens {
+		start, end := token[0], token[1]
+		for end < len(content) && (content[end] == ' ' || content[end] == '\t') {
+			end++
+		}
+		if end == token[1] {
+			for start > last && (content[start-1] == ' ' || content[start-1] == '\t') {
+				start--
+			}
+		}
+		rest.WriteString(content[last:start])
+		last = end
+	}
+	rest.WriteString(content[last:])
+	return strings.TrimSpace(rest.String())
+}
+
+// relativePattern matches offsets like 3d, +2w or 1m
+var relativePattern = regexp.MustCompile(`^\+?(\d+)([dwm])$`)
+
+// ParseDate parses a day relative to now and returns its local midnight.
+// It accepts today, tomorrow (tmr), yesterday, a weekday name (the next
+// such day, today included), next-week (next Monday), next-month (the
+// first of next month), an offset like 3d, 2w or 1m, and YYYY-MM-DD.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	now = now.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch value {
+	case "today":
+		return today, nil
+	case "tomorrow", "tmr":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "next-week", "nextweek":
+		days := (8 - int(today.Weekday())) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), nil
+	case "next-month", "nextmonth":
+		return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, time.Local), nil
+	}
+
+	if weekday, ok := parseWeekday(value); ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if match := relativePattern.FindStringSubmatch(value); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "d":
+			return today.AddDate(0, 0, n), nil
+		case "w":
+			return today.AddDate(0, 0, 7*n), nil
+		default:
+			return today.AddDate(0, n, 0), nil
+		}
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised date %q (try today, tomorrow, friday, 3d, 2w or 2006-01-02)", value)
+}
+
+// ParseSince parses the start of a period ending now, for reports: an
+// offset like 3d, 2w or 1m counts back from today and a weekday name is
+// its most recent day, today included. Other values are read by ParseDate.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	now = now.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if match := relativePattern.FindStringSubmatch(value); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "d":
+			return today.AddDate(0, 0, -n), nil
+		case "w":
+			return today.AddDate(0, 0, -7*n), nil
+		default:
+			return today.AddDate(0, -n, 0), nil
+		}
+	}
+
+	if weekday, ok := parseWeekday(value); ok {
+		days := (int(today.Weekday()) - int(weekday) + 7) % 7
+		return today.AddDate(0, 0, -days), nil
+	}
+
+	return ParseDate(value, now)
+}
+
+// parseWeekday parses a weekday's full or three-letter name
+func parseWeekday(value string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// IsOverdue reports whether the note was due before today and is not done
+func (n *Note) IsOverdue(now time.Time) bool {
+	if n.DueAt == nil || n.Status == "done" {
+		return false
+	}
+	now = now.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return n.DueAt.Before(today)
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+// testNow is a Wednesday afternoon, the day tests resolve dates against
+var testNow = time.Date(2026, 10, 14, 15, 30, 0, 0, time.Local)
+
+// day returns local midnight on the given day of October 2026
+func day(d int) *time.Time {
+	t := time.Date(2026, 10, d, 0, 0, 0, 0, time.Local)
+	return &t
+}
+
+func TestParseDates(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		want      string
+		due       *time.Time
+		scheduled *time.Time
+	}{
+		{"no tokens", "Buy milk", "Buy milk", nil, nil},
+		{"due", "Send the report due:friday", "Send the report", day(16), nil},
+		{"scheduled", "Plan the offsite scheduled:tomorrow", "Plan the offsite", nil, day(15)},
+		{"at date", "Call Bob @today", "Call Bob", nil, day(14)},
+		{"both", "due:2026-10-20 Renew @3d the domain", "Renew the domain", day(20), day(17)},
+		{"last wins", "Pay due:today rent due:tomorrow", "Pay rent", day(15), nil},
+		{"mention kept", "Ask @alice about it due:2w", "Ask @alice about it", day(28), nil},
+		{"email kept", "Mail bob@today.com", "Mail bob@today.com", nil, nil},
+		{"trailing punctuation", "Call Bob due:friday.", "Call Bob.", day(16), nil},
+		{"token starts a line", "Title\ndue:fri body", "Title\nbody", day(16), nil},
+		{"token ends a line", "Title due:fri\nbody", "Title\nbody", day(16), nil},
+		{"token alone on a line", "Title\n\ndue:fri\n\nbody", "Title\n\n\n\nbody", day(16), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, due, scheduled, err := ParseDates(tt.content, testNow)
+			if err != nil {
+				t.Fatalf("ParseDates(%q) error: %v", tt.content, err)
+			}
+			if got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+			if !sameTime(due, tt.due) {
+				t.Errorf("due = %v, want %v", due, tt.due)
+			}
+			if !sameTime(scheduled, tt.scheduled) {
+				t.Errorf("scheduled = %v, want %v", scheduled, tt.scheduled)
+			}
+		})
+	}
+}
+
+func TestParseDatesInvalid(t *testing.T) {
+	for _, content := range []string{"Pay rent due:someday", "Plan scheduled:2026-13-01", "Ship it\ndue:3x"} {
+		if _, _, _, err := ParseDates(content, testNow); err == nil {
+			t.Errorf("ParseDates(%q) gave no error", content)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *time.Time
+	}{
+		{"today", day(14)},
+		{"Tomorrow", day(15)},
+		{"tmr", day(15)},
+		{"yesterday", day(13)},
+		{"wednesday", day(14)},
+		{"fri", day(16)},
+		{"mon", day(19)},
+		{"next-week", day(19)},
+		{"next-month", func() *time.Time { t := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local); return &t }()},
+		{"3d", day(17)},
+		{"+2w", day(28)},
+		{"2026-10-31", day(31)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDate(tt.value, testNow)
+		if err != nil {
+			t.Errorf("ParseDate(%q) error: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(*tt.want) {
+			t.Errorf("ParseDate(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "someday", "3x", "2026-02-30"} {
+		if _, err := ParseDate(value, testNow); err == nil {
+			t.Errorf("ParseDate(%q) gave no error", value)
+		}
+	}
+}
+
+// sameTime reports whether two optional times are both nil or equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StatusEvent records a note moving from one status to another. From is
+// "" for the event that records the note's creation.
+type StatusEvent struct {
+	ID     int       `json:"id"`
+	NoteID int       `json:"note_id"`
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to"`
+	At     time.Time `json:"at"`
+}
+
+// GetStatusEvents retrieves the status events of every note that still
+// exists, oldest first
+func (s *Storage) GetStatusEvents() ([]*StatusEvent, error) {
+	query := `
+		SELECT e.id, e.note_id, e.from_status, e.to_status, e.at
+		FROM status_events AS e
+		JOIN notes ON notes.id = e.note_id
+		ORDER BY e.at ASC, e.id ASC
+	`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*StatusEvent
+	for rows.Next() {
+		var event StatusEvent
+		var from sql.NullString
+		if err := rows.Scan(&event.ID, &event.NoteID, &from, &event.To, &event.At); err != nil {
+			return nil, fmt.Errorf("failed to scan status event: %w", err)
+		}
+		event.From = from.String
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+// logStatusEvent records in tx that a note moved from one status to
+// another, or was created when from is ""
+func logStatusEvent(tx *sql.Tx, noteID int, from, to string, at time.Time) error {
+	query := `INSERT INTO status_events (note_id, from_status, to_status, at) VALUES (?, ?, ?, ?)`
+	if _, err := tx.Exec(query, noteID, nullString(from), to, at); err != nil {
+		return fmt.Errorf("failed to log status of note %d: %w", noteID, err)
+	}
+	return nil
+}
+
+// migrateStatusEvents creates the status event log. Notes that predate it
+// get one event for entering their current status when it last changed,
+// which is all that is known of their history.
+func (s *Storage) migrateStatusEvents() error {
+	var exists int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'status_events'`).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to inspect status events: %w", err)
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	return s.withTx(func(tx *sql.Tx) error {
+		schema := `
+			CREATE TABLE status_events (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				note_id INTEGER NOT NULL,
+				from_status TEXT,
+				to_status TEXT NOT NULL,
+				at DATETIME NOT NULL
+			);
+
+			CREATE INDEX idx_status_events_note_id ON status_events(note_id);
+			CREATE INDEX idx_status_events_at ON status_events(at);
+		`
+		if _, err := tx.Exec(schema); err != nil {
+			return fmt.Errorf("failed to create status events table: %w", err)
+		}
+
+		backfill := `
+			INSERT INTO status_events (note_id, from_status, to_status, at)
+			SELECT id, NULL, status, COALESCE(status_changed_at, created_at) FROM notes;
+		`
+		if _, err := tx.Exec(backfill); err != nil {
+			return fmt.Errorf("failed to backfill status events: %w", err)
+		}
+		return nil
+	})
+}
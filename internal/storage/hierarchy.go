@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Progress counts the subtasks below a note and how many of them are done
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// subtreeQuery selects the IDs of a note and every note below it, with
+// their depth below it
+const subtreeQuery = `
+	WITH RECURSIVE subtree(id, depth) AS (
+		SELECT id, 0 FROM notes WHERE id = ?
+		UNION ALL
+		SELECT notes.id, subtree.depth + 1
+		FROM notes JOIN subtree ON notes.parent_id = subtree.id
+	)
+`
+
+// GetSubtree retrieves a note and every note below it, at any depth,
+// parents before their children and siblings oldest first
+func (s *Storage) GetSubtree(id int) ([]*Note, error) {
+	query := subtreeQuery + `
+		SELECT ` + noteColumns + `
+		FROM notes JOIN subtree USING (id)
+		ORDER BY depth ASC, created_at ASC, id ASC
+	`
+	rows, err := s.db.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subtree: %w", err)
+	}
+	defer rows.Close()
+
+	notes, err := scanNotes(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("note with ID %d not found", id)
+	}
+	return notes, nil
+}
+
+// GetProgress returns the rolled-up progress of every note with subtasks,
+// counting the notes at every depth below it
+func (s *Storage) GetProgress() (map[int]Progress, error) {
+	query := `
+		WITH RECURSIVE descendants(ancestor, id) AS (
+			SELECT parent_id, id FROM notes WHERE parent_id IS NOT NULL
+			UNION ALL
+			SELECT descendants.ancestor, notes.id
+			FROM notes JOIN descendants ON notes.parent_id = descendants.id
+		)
+		SELECT descendants.ancestor, COUNT(*), SUM(notes.status = 'done')
+		FROM descendants JOIN notes ON notes.id = descendants.id
+		GROUP BY descendants.ancestor
+	`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query progress: %w", err)
+	}
+	defer rows.Close()
+
+	progress := make(map[int]Progress)
+	for rows.Next() {
+		var id int
+		var p Progress
+		if err := rows.Scan(&id, &p.Total, &p.Done); err != nil {
+			return nil, fmt.Errorf("failed to scan progress: %w", err)
+		}
+		progress[id] = p
+	}
+	return progress, rows.Err()
+}
+
+// SetParent makes a note a subtask of parentID, or moves it to the top
+// level when parentID is 0. A note cannot be placed below itself.
+func (s *Storage) SetParent(id, parentID int) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		if _, err := noteStatus(tx, id); err != nil {
+			return err
+		}
+
+		if parentID != 0 {
+			var below bool
+			err := tx.QueryRow(subtreeQuery+`SELECT EXISTS (SELECT 1 FROM subtree WHERE id = ?)`, id, parentID).Scan(&below)
+			if err != nil {
+				return fmt.Errorf("failed to check parent: %w", err)
+			}
+			if below {
+				return fmt.Errorf("#%d is #%d or one of its subtasks", parentID, id)
+			}
+			if _, err := noteStatus(tx, parentID); err != nil {
+				return fmt.Errorf("invalid parent: %w", err)
+			}
+		}
+
+		query := `UPDATE notes SET parent_id = ?, updated_at = ? WHERE id = ?`
+		if _, err := tx.Exec(query, nullInt(parentID), time.Now(), id); err != nil {
+			return fmt.Errorf("failed to set parent of note %d: %w", id, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Priorities run from P0, the most urgent, to P3
+const (
+	MinPriority = 0
+	MaxPriority = 3
+)
+
+// priorityTokenPattern matches priority tokens written in note content:
+// !p0 to !p3, !!! for P0 and !! for P1. Only whole words count, although
+// they may be followed by punctuation (see priorityTokenEnd).
+var priorityTokenPattern = regexp.MustCompile(`(?i)!p[0-3]|!!!|!!`)
+
+// ParsePriority extracts a priority written in content as !p0 to !p3,
+// !!! (P0) or !! (P1), returning the content with the token removed. The
+// last token wins; nil means no priority was written.
+func ParsePriority(content string) (string, *int) {
+	var priority *int
+	var tokens [][2]int
+
+	for _, match := range priorityTokenPattern.FindAllStringIndex(content, -1) {
+		if !tokenStart(content, match[0]) || !priorityTokenEnd(content, match[1]) {
+			continue
+		}
+		token := strings.ToLower(content[match[0]:match[1]])
+
+		var p int
+		switch token {
+		case "!!!":
+			p = 0
+		case "!!":
+			p = 1
+		default:
+			p = int(token[2] - '0')
+		}
+		priority = &p
+		tokens = append(tokens, [2]int{match[0], match[1]})
+	}
+
+	return cutTokens(content, tokens), priority
+}
+
+// priorityTokenEnd reports whether a priority token ending at i in content
+// ends a word: it is at the end of content or followed by whitespace or
+// punctuation
+func priorityTokenEnd(content string, i int) bool {
+	return i == len(content) || unicode.IsSpace(rune(content[i])) || strings.ContainsRune(".,;:?", rune(content[i]))
+}
+
+// ParsePriorityValue parses a priority given on its own: p0 to p3, or the
+// bare digit
+func ParsePriorityValue(value string) (int, error) {
+	digits := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "p")
+	if len(digits) == 1 && digits[0] >= '0'+MinPriority && digits[0] <= '0'+MaxPriority {
+		return int(digits[0] - '0'), nil
+	}
+	return 0, fmt.Errorf("invalid priority %q (use p0 to p3)", value)
+}
+
+// FormatPriority formats a priority as P0 to P3, or "" for none
+func FormatPriority(priority *int) string {
+	if priority == nil {
+		return ""
+	}
+	return fmt.Sprintf("P%d", *priority)
+}
+
+// PriorityRank orders notes by priority: P0 first and notes without a
+// priority after P3
+func (n *Note) PriorityRank() int {
+	if n.Priority == nil {
+		return MaxPriority + 1
+	}
+	return *n.Priority
+}
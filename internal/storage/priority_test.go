@@ -0,0 +1,53 @@
+package storage
+
+import "testing"
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		want     string
+		priority string
+	}{
+		{"none", "Update the docs", "Update the docs", ""},
+		{"p2", "Update the docs !p2", "Update the docs", "P2"},
+		{"upper case", "!P3 Update the docs", "Update the docs", "P3"},
+		{"bangs", "Database is down !!!", "Database is down", "P0"},
+		{"two bangs", "Database is slow !!", "Database is slow", "P1"},
+		{"last token wins", "Fix it !p3 now !!", "Fix it now", "P1"},
+		{"punctuation kept", "Fix the build !p1.", "Fix the build.", "P1"},
+		{"exclamation kept", "Fix it!! now", "Fix it!! now", ""},
+		{"four bangs kept", "Wow !!!!", "Wow !!!!", ""},
+		{"not a priority", "Ship !p4 and !pp", "Ship !p4 and !pp", ""},
+		{"token starts a line", "a\n!!! b", "a\nb", "P0"},
+		{"token ends a line", "a !!\nb", "a\nb", "P1"},
+		{"adjacent tokens", "x !!\n!p2", "x", "P2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, priority := ParsePriority(tt.content)
+			if got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+			if FormatPriority(priority) != tt.priority {
+				t.Errorf("priority = %q, want %q", FormatPriority(priority), tt.priority)
+			}
+		})
+	}
+}
+
+func TestParsePriorityValue(t *testing.T) {
+	for value, want := range map[string]int{"p0": 0, "P1": 1, "2": 2, " p3 ": 3} {
+		got, err := ParsePriorityValue(value)
+		if err != nil || got != want {
+			t.Errorf("ParsePriorityValue(%q) = %d, %v, want %d", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "p4", "-1", "p", "p01", "high"} {
+		if _, err := ParsePriorityValue(value); err == nil {
+			t.Errorf("ParsePriorityValue(%q) gave no error", value)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies
+const (
+	FreqDaily   = "daily"
+	FreqWeekly  = "weekly"
+	FreqMonthly = "monthly"
+	FreqYearly  = "yearly"
+)
+
+// Recurrence is a parsed recurrence rule: every Interval days, weeks,
+// months or years, optionally only on some weekdays or a day of the
+// month, and optionally ending after Count occurrences or on Until
+type Recurrence struct {
+	Freq     string
+	Interval int
+	Weekdays []time.Weekday // Days a daily or weekly rule falls on, empty for any
+	MonthDay int            // Day a monthly rule falls on, 0 for the due date's
+	Count    int            // Occurrences in the series, 0 for unlimited
+	Until    *time.Time     // Last day an occurrence may fall on, nil for no end
+}
+
+// recurrenceTokenPattern matches every:<rule> written in note content.
+// Only tokens starting a word count (see tokenStart).
+var recurrenceTokenPattern = regexp.MustCompile(`every:(\S+)`)
+
+// intervalPattern matches shorthand intervals like 2d, 2w, 3m or 1y
+var intervalPattern = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// ParseRecurrenceToken extracts a recurrence rule written in content as
+// every:<rule>, returning the content with the token removed and the rule
+// as written, or "" if there is none. An invalid rule is an error.
+func ParseRecurrenceToken(content string) (string, string, error) {
+	var rule string
+	var tokens [][2]int
+
+	for _, match := range recurrenceTokenPattern.FindAllStringSubmatchIndex(content, -1) {
+		if !tokenStart(content, match[0]) {
+			continue
+		}
+		value := strings.TrimRight(content[match[2]:match[3]], ".,!?;")
+		if _, err := ParseRecurrence(value); err != nil {
+			return "", "", fmt.Errorf("invalid rule in every:%s: %w", value, err)
+		}
+		rule = value
+		tokens = append(tokens, [2]int{match[0], match[2] + len(value)})
+	}
+
+	return cutTokens(content, tokens), rule, nil
+}
+
+// ParseRecurrence parses a recurrence rule. Shorthands are daily, weekly,
+// monthly, yearly, weekday (Monday to Friday), weekend, an interval like
+// 2d, 2w, 3m or 1y, and weekday names like mon or mon,thu. Anything else
+// is read as an RRULE (RFC 5545), with or without the RRULE: prefix,
+// supporting FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL.
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	value := strings.ToLower(strings.TrimSpace(rule))
+
+	switch value {
+	case "":
+		return nil, fmt.Errorf("empty recurrence rule")
+	case "daily", "day":
+		return &Recurrence{Freq: FreqDaily, Interval: 1}, nil
+	case "weekly", "week":
+		return &Recurrence{Freq: FreqWeekly, Interval: 1}, nil
+	case "monthly", "month":
+		return &Recurrence{Freq: FreqMonthly, Interval: 1}, nil
+	case "yearly", "year", "annually":
+		return &Recurrence{Freq: FreqYearly, Interval: 1}, nil
+	case "weekday", "weekdays":
+		return &Recurrence{Freq: FreqWeekly, Interval: 1,
+			Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}, nil
+	case "weekend", "weekends":
+		return &Recurrence{Freq: FreqWeekly, Interval: 1, Weekdays: []time.Weekday{time.Saturday, time.Sunday}}, nil
+	}
+
+	if match := intervalPattern.FindStringSubmatch(value); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		if n < 1 {
+			return nil, fmt.Errorf("interval must be at least 1")
+		}
+		freq := map[string]string{"d": FreqDaily, "w": FreqWeekly, "m": FreqMonthly, "y": FreqYearly}[match[2]]
+		return &Recurrence{Freq: freq, Interval: n}, nil
+	}
+
+	if weekdays, ok := parseWeekdayList(value); ok {
+		return &Recurrence{Freq: FreqWeekly, Interval: 1, Weekdays: weekdays}, nil
+	}
+
+	if strings.HasPrefix(value, "rrule:") || strings.Contains(value, "freq=") {
+		return parseRRule(strings.TrimPrefix(value, "rrule:"))
+	}
+
+	return nil, fmt.Errorf("unrecognised rule %q (try weekday, 2w, mon,thu or FREQ=WEEKLY;INTERVAL=2)", rule)
+}
+
+// parseWeekdayList parses comma-separated weekday names like mon,thu
+func parseWeekdayList(value string) ([]time.Weekday, bool) {
+	var weekdays []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		weekday, ok := parseWeekday(name)
+		if !ok {
+			return nil, false
+		}
+		weekdays = append(weekdays, weekday)
+	}
+	return weekdays, true
+}
+
+// rruleDays maps RRULE weekday codes to weekdays
+var rruleDays = map[string]time.Weekday{
+	"su": time.Sunday, "mo": time.Monday, "tu": time.Tuesday, "we": time.Wednesday,
+	"th": time.Thursday, "fr": time.Friday, "sa": time.Saturday,
+}
+
+// parseRRule parses the supported subset of an RRULE, given lowercased
+// and without its prefix
+func parseRRule(value string) (*Recurrence, error) {
+	r := &Recurrence{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+
+		switch key {
+		case "freq":
+			switch val {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				r.Freq = val
+			default:
+				return nil, fmt.Errorf("unsupported RRULE frequency %q", val)
+			}
+		case "interval":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE interval %q", val)
+			}
+			r.Interval = n
+		case "byday":
+			for _, code := range strings.Split(val, ",") {
+				weekday, ok := rruleDays[code]
+				if !ok {
+					return nil, fmt.Errorf("unsupported RRULE day %q", code)
+				}
+				r.Weekdays = append(r.Weekdays, weekday)
+			}
+		case "bymonthday":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 31 {
+				return nil, fmt.Errorf("invalid RRULE month day %q", val)
+			}
+			r.MonthDay = n
+		case "count":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE count %q", val)
+			}
+			r.Count = n
+		case "until":
+			if len(val) < 8 {
+				return nil, fmt.Errorf("invalid RRULE until %q", val)
+			}
+			until, err := time.ParseInLocation("20060102", val[:8], time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE until %q", val)
+			}
+			r.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part %q", strings.ToUpper(key))
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("RRULE needs a FREQ")
+	}
+	if len(r.Weekdays) > 0 && r.Freq != FreqDaily && r.Freq != FreqWeekly {
+		return nil, fmt.Errorf("BYDAY is only supported with FREQ=DAILY or FREQ=WEEKLY")
+	}
+	if r.MonthDay > 0 && r.Freq != FreqMonthly {
+		return nil, fmt.Errorf("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return r, nil
+}
+
+// onWeekday reports whether day is one of the rule's weekdays, or any
+// day when it has none
+func (r *Recurrence) onWeekday(day time.Time) bool {
+	if len(r.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range r.Weekdays {
+		if day.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// First returns the first occurrence on or after from, a local midnight
+func (r *Recurrence) First(from time.Time) time.Time {
+	switch {
+	case r.Freq == FreqMonthly && r.MonthDay > 0:
+		if day := monthDay(from.Year(), from.Month(), r.MonthDay); !day.Before(from) {
+			return day
+		}
+	case r.onWeekday(from):
+		return from
+	}
+	return r.Next(from)
+}
+
+// Next returns the first occurrence after prev, a local midnight. A
+// monthly or yearly rule without a day follows prev's day, so a series
+// due on the 31st moves to the 28th after February and stays there.
+func (r *Recurrence) Next(prev time.Time) time.Time {
+	switch r.Freq {
+	case FreqDaily:
+		next := prev.AddDate(0, 0, r.Interval)
+		for i := 0; i < 7 && !r.onWeekday(next); i++ {
+			next = next.AddDate(0, 0, r.Interval)
+		}
+		return next
+
+	case FreqWeekly:
+		if len(r.Weekdays) == 0 {
+			return prev.AddDate(0, 0, 7*r.Interval)
+		}
+		// Later days in prev's week (Monday to Sunday) come first, then
+		// the first matching day Interval weeks on
+		weekStart := prev.AddDate(0, 0, -((int(prev.Weekday()) + 6) % 7))
+		for day := prev.AddDate(0, 0, 1); day.Before(weekStart.AddDate(0, 0, 7)); day = day.AddDate(0, 0, 1) {
+			if r.onWeekday(day) {
+				return day
+			}
+		}
+		next := weekStart.AddDate(0, 0, 7*r.Interval)
+		for !r.onWeekday(next) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+
+	case FreqMonthly:
+		day := r.MonthDay
+		if day == 0 {
+			day = prev.Day()
+		}
+		return monthDay(prev.Year(), prev.Month()+time.Month(r.Interval), day)
+
+	default:
+		return monthDay(prev.Year()+r.Interval, prev.Month(), prev.Day())
+	}
+}
+
+// monthDay returns the given day of a month at local midnight, clamped to
+// the month's last day, so the 31st falls on the 30th in April
+func monthDay(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// recur keeps a recurring series going when a note's status changes from
+// one status to another in tx. Completing a recurring note spawns its next
+// occurrence in todo and moves the rule to it, so completing the note
+// again spawns nothing. Reopening a completed note withdraws the next
+// occurrence again if it is still untouched, which makes undo clean.
+func recur(tx *sql.Tx, id int, from, to string, now time.Time) error {
+	switch {
+	case from != "done" && to == "done":
+		return spawnNext(tx, id, now)
+	case from == "done" && to != "done":
+		return withdrawNext(tx, id)
+	}
+	return nil
+}
+
+// spawnNext creates the next occurrence of a recurring note that has just
+// been completed, unless the series has ended. It is due on the rule's
+// first occurrence after the note's due date that is also after today,
+// and keeps its scheduled date the same distance before that. Its
+// checklist starts over, with every item unchecked.
+func spawnNext(tx *sql.Tx, id int, now time.Time) error {
+	row := tx.QueryRow(`SELECT `+noteColumns+` FROM notes WHERE id = ? AND recurrence != ''`, id)
+	note, err := scanNote(row)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read recurring note %d: %w", id, err)
+	}
+
+	rule, err := ParseRecurrence(note.Recurrence)
+	if err != nil {
+		return fmt.Errorf("note %d has an invalid recurrence: %w", id, err)
+	}
+
+	// The series is named after its first note
+	seriesID := note.SeriesID
+	if seriesID == 0 {
+		seriesID = note.ID
+	}
+	if _, err := tx.Exec(`UPDATE notes SET recurrence = NULL, series_id = ? WHERE id = ?`, seriesID, id); err != nil {
+		return fmt.Errorf("failed to update recurring note %d: %w", id, err)
+	}
+
+	now = now.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	anchor := today
+	if note.DueAt != nil {
+		anchor = *note.DueAt
+	} else if note.ScheduledAt != nil {
+		anchor = *note.ScheduledAt
+	}
+
+	next := rule.Next(anchor)
+	for !next.After(today) {
+		next = rule.Next(next)
+	}
+
+	if rule.Until != nil && next.After(*rule.Until) {
+		return nil
+	}
+	if rule.Count > 0 {
+		var occurrences int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM notes WHERE series_id = ?`, seriesID).Scan(&occurrences); err != nil {
+			return fmt.Errorf("failed to count series %d: %w", seriesID, err)
+		}
+		if occurrences >= rule.Count {
+			return nil
+		}
+	}
+
+	occurrence := &Note{
+		Title:      note.Title,
+		Content:    ResetChecklist(note.Content),
+		Status:     "todo",
+		Tags:       note.Tags,
+		Priority:   note.Priority,
+		Recurrence: note.Recurrence,
+		SeriesID:   seriesID,
+		ParentID:   note.ParentID,
+
+		spawnedFrom: note.ID,
+	}
+	if note.DueAt != nil || note.ScheduledAt == nil {
+		occurrence.DueAt = &next
+	}
+	if note.ScheduledAt != nil {
+		days := int(math.Round(next.Sub(anchor).Hours() / 24))
+		scheduledAt := note.ScheduledAt.AddDate(0, 0, days)
+		occurrence.ScheduledAt = &scheduledAt
+	}
+	return insertNote(tx, occurrence, now)
+}
+
+// withdrawNext deletes the occurrence spawned when a note in a series was
+// completed, if it has not been changed since, and moves the rule back to
+// the note. Only the latest completed note has an occurrence holding the
+// rule, so reopening an earlier one withdraws nothing.
+func withdrawNext(tx *sql.Tx, id int) error {
+	query := `
+		SELECT next.id, next.recurrence
+		FROM notes AS note
+		JOIN notes AS next ON next.spawned_from = note.id
+		WHERE note.id = ? AND note.recurrence IS NULL
+			AND next.recurrence != '' AND next.status = 'todo' AND next.created_at = next.updated_at
+	`
+	var nextID int
+	var rule string
+	err := tx.QueryRow(query, id).Scan(&nextID, &rule)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find the next occurrence of note %d: %w", id, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM notes WHERE id = ?`, nextID); err != nil {
+		return fmt.Errorf("failed to withdraw note %d: %w", nextID, err)
+	}
+	if _, err := tx.Exec(`UPDATE notes SET recurrence = ? WHERE id = ?`, rule, id); err != nil {
+		return fmt.Errorf("failed to update recurring note %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestStorage opens a fresh database in a temporary home directory
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := New()
+	if err != nil {
+		t.Fatalf("failed to open storage: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// nextOccurrence returns the todo note spawned by completing a recurring
+// note
+func nextOccurrence(t *testing.T, s *Storage, id int) *Note {
+	t.Helper()
+	notes, err := s.GetNotesByStatus("todo", "")
+	if err != nil {
+		t.Fatalf("failed to list todo notes: %v", err)
+	}
+	for _, note := range notes {
+		if note.ID != id && note.SeriesID == id {
+			return note
+		}
+	}
+	t.Fatalf("no next occurrence of note %d", id)
+	return nil
+}
+
+func TestRecurringChecklistStartsUnchecked(t *testing.T) {
+	s := newTestStorage(t)
+
+	content := strings.Join([]string{
+		"- [x] Water the ferns",
+		"- [X] Water the cactus",
+		"- [ ] Feed the fish",
+		"```",
+		"- [x] not an item",
+		"```",
+	}, "\n")
+	note := &Note{Title: "Chores", Content: content, Recurrence: "daily"}
+	if err := s.CreateNote(note); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+	if err := s.UpdateNoteStatus(note.ID, "done"); err != nil {
+		t.Fatalf("failed to complete note: %v", err)
+	}
+
+	next := nextOccurrence(t, s, note.ID)
+	want := strings.Join([]string{
+		"- [ ] Water the ferns",
+		"- [ ] Water the cactus",
+		"- [ ] Feed the fish",
+		"```",
+		"- [x] not an item",
+		"```",
+	}, "\n")
+	if next.Content != want {
+		t.Errorf("next occurrence content = %q, want %q", next.Content, want)
+	}
+	if checked, total := ChecklistProgress(next.Content); checked != 0 || total != 3 {
+		t.Errorf("next occurrence progress = %d/%d, want 0/3", checked, total)
+	}
+
+	completed, err := s.GetNote(note.ID)
+	if err != nil {
+		t.Fatalf("failed to read completed note: %v", err)
+	}
+	if completed.Content != content {
+		t.Errorf("completed note content changed to %q", completed.Content)
+	}
+}
+
+func TestRecurringChecklistWithAutoDone(t *testing.T) {
+	s := newTestStorage(t)
+	s.SetAutoDone(true)
+
+	note := &Note{Title: "Standup", Content: "- [x] Read the board\n- [ ] Post an update", Recurrence: "weekday"}
+	if err := s.CreateNote(note); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+
+	checked, err := s.CheckItem(note.ID, 2)
+	if err != nil {
+		t.Fatalf("failed to check item: %v", err)
+	}
+	if checked.Status != "done" {
+		t.Fatalf("status after checking every item = %q, want done", checked.Status)
+	}
+
+	next := nextOccurrence(t, s, note.ID)
+	if next.Status != "todo" {
+		t.Errorf("next occurrence status = %q, want todo", next.Status)
+	}
+	if done, total := ChecklistProgress(next.Content); done != 0 || total != 2 {
+		t.Errorf("next occurrence progress = %d/%d, want 0/2", done, total)
+	}
+
+	// Checking an item of the new occurrence leaves it open
+	next, err = s.CheckItem(next.ID, 1)
+	if err != nil {
+		t.Fatalf("failed to check item: %v", err)
+	}
+	if next.Status != "todo" {
+		t.Errorf("status after checking one of two items = %q, want todo", next.Status)
+	}
+}
+
+// date returns local midnight on the given day
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestParseRecurrenceToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		rule    string
+	}{
+		{"none", "Water the plants", "Water the plants", ""},
+		{"interval", "Water the plants every:2d", "Water the plants", "2d"},
+		{"last wins", "every:daily Standup every:weekday", "Standup", "weekday"},
+		{"punctuation kept", "Review the week every:fri.", "Review the week.", "fri"},
+		{"not a token", "Keep forever:daily", "Keep forever:daily", ""},
+		{"token starts a line", "Title\nevery:daily body", "Title\nbody", "daily"},
+		{"token ends a line", "Title every:daily\nbody", "Title\nbody", "daily"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rule, err := ParseRecurrenceToken(tt.content)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceToken(%q) error: %v", tt.content, err)
+			}
+			if got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+			if rule != tt.rule {
+				t.Errorf("rule = %q, want %q", rule, tt.rule)
+			}
+		})
+	}
+
+	if _, _, err := ParseRecurrenceToken("Water the plants every:sometimes"); err == nil {
+		t.Error("an invalid rule gave no error")
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	until := date(2026, time.December, 31)
+	tests := []struct {
+		rule string
+		want Recurrence
+	}{
+		{"daily", Recurrence{Freq: FreqDaily, Interval: 1}},
+		{"Weekly", Recurrence{Freq: FreqWeekly, Interval: 1}},
+		{"monthly", Recurrence{Freq: FreqMonthly, Interval: 1}},
+		{"annually", Recurrence{Freq: FreqYearly, Interval: 1}},
+		{"weekday", Recurrence{Freq: FreqWeekly, Interval: 1, Weekdays: weekdays}},
+		{"weekend", Recurrence{Freq: FreqWeekly, Interval: 1, Weekdays: []time.Weekday{time.Saturday, time.Sunday}}},
+		{"2d", Recurrence{Freq: FreqDaily, Interval: 2}},
+		{"2w", Recurrence{Freq: FreqWeekly, Interval: 2}},
+		{"3m", Recurrence{Freq: FreqMonthly, Interval: 3}},
+		{"1y", Recurrence{Freq: FreqYearly, Interval: 1}},
+		{"fri", Recurrence{Freq: FreqWeekly, Interval: 1, Weekdays: []time.Weekday{time.Friday}}},
+		{"mon,thu", Recurrence{Freq: FreqWeekly, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Thursday}}},
+		{"FREQ=WEEKLY;INTERVAL=2", Recurrence{Freq: FreqWeekly, Interval: 2}},
+		{"FREQ=MONTHLY;BYMONTHDAY=1", Recurrence{Freq: FreqMonthly, Interval: 1, MonthDay: 1}},
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO,FR;COUNT=5;UNTIL=20261231T000000Z", Recurrence{
+			Freq: FreqWeekly, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Friday}, Count: 5, Until: &until}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) error: %v", tt.rule, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("ParseRecurrence(%q) = %+v, want %+v", tt.rule, *got, tt.want)
+		}
+	}
+
+	invalid := []string{
+		"", "sometimes", "0d", "mon,someday", "INTERVAL=2", "FREQ=HOURLY", "FREQ=WEEKLY;INTERVAL=0",
+		"FREQ=DAILY;BYSETPOS=1", "FREQ=DAILY;COUNT=x", "FREQ=DAILY;UNTIL=2026", "FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=MO", "FREQ=WEEKLY;BYMONTHDAY=3", "FREQ=MONTHLY;BYMONTHDAY=32",
+	}
+	for _, rule := range invalid {
+		if _, err := ParseRecurrence(rule); err == nil {
+			t.Errorf("ParseRecurrence(%q) gave no error", rule)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	tests := []struct {
+		rule string
+		prev time.Time
+		want time.Time
+	}{
+		{"daily", date(2026, time.October, 14), date(2026, time.October, 15)},
+		{"2d", date(2026, time.October, 14), date(2026, time.October, 16)},
+		{"weekly", date(2026, time.October, 14), date(2026, time.October, 21)},
+		{"weekday", date(2026, time.October, 16), date(2026, time.October, 19)},
+		{"weekend", date(2026, time.October, 14), date(2026, time.October, 17)},
+		{"weekend", date(2026, time.October, 17), date(2026, time.October, 18)},
+		{"weekend", date(2026, time.October, 18), date(2026, time.October, 24)},
+		{"mon,thu", date(2026, time.October, 12), date(2026, time.October, 15)},
+		{"mon,thu", date(2026, time.October, 15), date(2026, time.October, 19)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", date(2026, time.October, 12), date(2026, time.October, 16)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", date(2026, time.October, 16), date(2026, time.October, 26)},
+		{"FREQ=DAILY;BYDAY=MO", date(2026, time.October, 14), date(2026, time.October, 19)},
+		{"monthly", date(2026, time.January, 31), date(2026, time.February, 28)},
+		{"3m", date(2026, time.October, 14), date(2027, time.January, 14)},
+		{"FREQ=MONTHLY;BYMONTHDAY=1", date(2026, time.October, 14), date(2026, time.November, 1)},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", date(2026, time.October, 31), date(2026, time.November, 30)},
+		{"yearly", date(2028, time.February, 29), date(2029, time.February, 28)},
+	}
+
+	for _, tt := range tests {
+		rule, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q) error: %v", tt.rule, err)
+		}
+		if got := rule.Next(tt.prev); !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tt.rule, tt.prev.Format("Mon 2006-01-02"),
+				got.Format("Mon 2006-01-02"), tt.want.Format("Mon 2006-01-02"))
+		}
+	}
+}
+
+func TestReopeningOlderOccurrenceKeepsSeries(t *testing.T) {
+	s := newTestStorage(t)
+
+	a := &Note{Title: "Water the plants", Recurrence: "daily"}
+	if err := s.CreateNote(a); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+	if err := s.UpdateNoteStatus(a.ID, "done"); err != nil {
+		t.Fatalf("failed to complete note: %v", err)
+	}
+	b := nextOccurrence(t, s, a.ID)
+	if err := s.UpdateNoteStatus(b.ID, "done"); err != nil {
+		t.Fatalf("failed to complete note: %v", err)
+	}
+	c := nextOccurrence(t, s, a.ID)
+
+	// Reopening A leaves B and C alone, as C was spawned by B
+	if err := s.UpdateNoteStatus(a.ID, "todo"); err != nil {
+		t.Fatalf("failed to reopen note: %v", err)
+	}
+	if _, err := s.GetNote(c.ID); err != nil {
+		t.Fatalf("occurrence spawned by B was withdrawn: %v", err)
+	}
+	b, err := s.GetNote(b.ID)
+	if err != nil {
+		t.Fatalf("failed to read note: %v", err)
+	}
+	if b.Status != "done" || b.Recurrence != "" {
+		t.Errorf("B = %s every:%q, want done without a rule", b.Status, b.Recurrence)
+	}
+	if reopened, _ := s.GetNote(a.ID); reopened.Recurrence != "" {
+		t.Errorf("reopened A took the rule %q", reopened.Recurrence)
+	}
+
+	// Reopening B, the latest completed note, withdraws C
+	if err := s.UpdateNoteStatus(b.ID, "todo"); err != nil {
+		t.Fatalf("failed to reopen note: %v", err)
+	}
+	if _, err := s.GetNote(c.ID); err == nil {
+		t.Error("occurrence spawned by B was not withdrawn")
+	}
+	if b, _ = s.GetNote(b.ID); b.Recurrence != "daily" {
+		t.Errorf("B rule = %q, want daily", b.Recurrence)
+	}
+}
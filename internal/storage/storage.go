@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -14,18 +15,144 @@ import (
 
 // Note represents a note in the system
 type Note struct {
-	ID        int       `json:"id"`
-	Content   string    `json:"content"`
+	ID int `json:"id"`
+
+	// Title is the note's first line, shown on cards and in lists, and
+	// Content its body: the lines after it, possibly empty (see SplitTitle)
+	Title   string `json:"title"`
+	Content string `json:"content"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Status    string    `json:"status"` // "todo", "doing", "done"
 	Tags      []string  `json:"tags"`
 	Embedding []float64 `json:"embedding,omitempty"`
+
+	// StatusChangedAt is when the note last entered its current status
+	StatusChangedAt time.Time `json:"status_changed_at"`
+
+	// ArchivedAt is when the note was archived off the board, nil if active
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
+	// DueAt is the day the note is due and ScheduledAt the day it is
+	// planned for, each at local midnight, or nil if unset
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+
+	// Priority runs from 0 (P0, most urgent) to 3, or nil if unset
+	Priority *int `json:"priority,omitempty"`
+
+	// Recurrence is the rule the note repeats by, as written (see
+	// ParseRecurrence), or "" if it does not repeat. SeriesID is the ID of
+	// the first note in its recurring series, or 0 if it is in none.
+	Recurrence string `json:"recurrence,omitempty"`
+	SeriesID   int    `json:"series_id,omitempty"`
+
+	// spawnedFrom is the ID of the note whose completion added this
+	// occurrence of a series, or 0, for withdrawNext to find it again
+	spawnedFrom int
+
+	// ParentID is the ID of the note this one is a subtask of, or 0 if it
+	// is at the top level (see GetSubtree)
+	ParentID int `json:"parent_id,omitempty"`
+
+	// subtasks are the IDs of the subtasks DeleteNotes moved to the top
+	// level, for RestoreNotes to move back under the note
+	subtasks []int
+}
+
+// Statuses lists the valid note statuses in board order
+var Statuses = []string{"todo", "doing", "done"}
+
+// IsValidStatus reports whether status is one of Statuses
+func IsValidStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// noteColumns is the column list read by scanNote
+const noteColumns = `id, title, content, status, tags, created_at, updated_at, status_changed_at, archived_at, due_at, scheduled_at, priority, recurrence, series_id, parent_id`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanNote scans a row selected with noteColumns, followed by any extra
+// destinations for additional selected columns
+func scanNote(row rowScanner, extra ...interface{}) (*Note, error) {
+	var note Note
+	var tagsJSON string
+	var statusChangedAt, archivedAt, dueAt, scheduledAt sql.NullTime
+	var priority, seriesID, parentID sql.NullInt64
+	var recurrence sql.NullString
+
+	dest := []interface{}{&note.ID, &note.Title, &note.Content, &note.Status, &tagsJSON, &note.CreatedAt, &note.UpdatedAt,
+		&statusChangedAt, &archivedAt, &dueAt, &scheduledAt, &priority, &recurrence, &seriesID, &parentID}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(tagsJSON), &note.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+
+	note.StatusChangedAt = note.UpdatedAt
+	if statusChangedAt.Valid {
+		note.StatusChangedAt = statusChangedAt.Time
+	}
+	if archivedAt.Valid {
+		note.ArchivedAt = &archivedAt.Time
+	}
+	if dueAt.Valid {
+		note.DueAt = &dueAt.Time
+	}
+	if scheduledAt.Valid {
+		note.ScheduledAt = &scheduledAt.Time
+	}
+	if priority.Valid {
+		p := int(priority.Int64)
+		note.Priority = &p
+	}
+	note.Recurrence = recurrence.String
+	note.SeriesID = int(seriesID.Int64)
+	note.ParentID = int(parentID.Int64)
+
+	return &note, nil
+}
+
+// scanNotes scans every row selected with noteColumns
+func scanNotes(rows *sql.Rows) ([]*Note, error) {
+	var notes []*Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan note row: %w", err)
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
 }
 
 // Storage handles all database operations
 type Storage struct {
 	db *sql.DB
+
+	// versionConn is the connection DataVersion polls. PRAGMA data_version
+	// is per connection, so it must always be read on the same one.
+	versionConn *sql.Conn
+
+	// autoTimer starts a note's timer as it moves into doing and stops it
+	// as it leaves (see SetAutoTimer)
+	autoTimer bool
+
+	// autoDone moves a note to done once every checklist item is checked
+	// (see SetAutoDone)
+	autoDone bool
 }
 
 // New creates a new Storage instance
@@ -55,53 +182,206 @@ func New() (*Storage, error) {
 
 // Close closes the database connection
 func (s *Storage) Close() error {
+	if s.versionConn != nil {
+		s.versionConn.Close()
+	}
 	return s.db.Close()
 }
 
-// AddNote adds a new note to the database
+// DataVersion returns a number that changes whenever the database is
+// modified through another connection, including by other processes.
+// Polling it lets a long-running view notice changes made elsewhere.
+func (s *Storage) DataVersion() (int64, error) {
+	ctx := context.Background()
+	if s.versionConn == nil {
+		conn, err := s.db.Conn(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to open connection: %w", err)
+		}
+		s.versionConn = conn
+	}
+
+	var version int64
+	if err := s.versionConn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read data version: %w", err)
+	}
+	return version, nil
+}
+
+// DatabaseSize returns the size of the database in bytes
+func (s *Storage) DatabaseSize() (int64, error) {
+	var pageCount, pageSize int64
+	if err := s.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("failed to read page count: %w", err)
+	}
+	if err := s.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to read page size: %w", err)
+	}
+	return pageCount * pageSize, nil
+}
+
+// AddNote adds a new note to the database, its first line as the title
 func (s *Storage) AddNote(content, status string, tags []string) (*Note, error) {
-	if status == "" {
-		status = "todo"
+	return s.AddDatedNote(content, status, tags, nil, nil)
+}
+
+// AddDatedNote adds a new note with due and scheduled days, either of
+// which may be nil. Notes with a priority or recurrence are built with
+// ParseNote and added with CreateNote.
+func (s *Storage) AddDatedNote(content, status string, tags []string, dueAt, scheduledAt *time.Time) (*Note, error) {
+	title, body := SplitTitle(content)
+	note := &Note{Title: title, Content: body, Status: status, Tags: tags, DueAt: dueAt, ScheduledAt: scheduledAt}
+	if err := s.CreateNote(note); err != nil {
+		return nil, err
 	}
+	return note, nil
+}
 
-	tagsJSON, err := json.Marshal(tags)
+// CreateNote inserts a note built with ParseNote or by hand, including its
+// dates, priority and recurrence, and fills in its ID and timestamps. A
+// recurring note with no due or scheduled date is due on its first
+// occurrence.
+func (s *Storage) CreateNote(note *Note) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return insertNote(tx, note, time.Now())
+	})
+}
+
+// insertNote inserts a note in tx as described by CreateNote and logs its
+// creation
+func insertNote(tx *sql.Tx, note *Note, now time.Time) error {
+	if note.Status == "" {
+		note.Status = "todo"
+	}
+	if err := defaultDueAt(note, now); err != nil {
+		return err
+	}
+
+	if note.ParentID != 0 {
+		if _, err := noteStatus(tx, note.ParentID); err != nil {
+			return fmt.Errorf("invalid parent: %w", err)
+		}
+	}
+
+	tagsJSON, err := json.Marshal(note.Tags)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal tags: %w", err)
+		return fmt.Errorf("failed to marshal tags: %w", err)
 	}
 
 	query := `
-		INSERT INTO notes (content, status, tags, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO notes (title, content, status, tags, created_at, updated_at, status_changed_at, due_at,
+			scheduled_at, priority, recurrence, series_id, parent_id, spawned_from)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	now := time.Now()
-	result, err := s.db.Exec(query, content, status, string(tagsJSON), now, now)
+	result, err := tx.Exec(query, note.Title, note.Content, note.Status, string(tagsJSON), now, now, now,
+		note.DueAt, note.ScheduledAt, note.Priority, nullString(note.Recurrence), nullInt(note.SeriesID),
+		nullInt(note.ParentID), nullInt(note.spawnedFrom))
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert note: %w", err)
+		return fmt.Errorf("failed to insert note: %w", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+		return fmt.Errorf("failed to get last insert id: %w", err)
 	}
 
-	return &Note{
-		ID:        int(id),
-		Content:   content,
-		Status:    status,
-		Tags:      tags,
-		CreatedAt: now,
-		UpdatedAt: now,
-	}, nil
+	note.ID = int(id)
+	note.CreatedAt = now
+	note.UpdatedAt = now
+	note.StatusChangedAt = now
+	return logStatusEvent(tx, note.ID, "", note.Status, now)
+}
+
+// defaultDueAt makes a recurring note with no due or scheduled date due
+// on its first occurrence from today, giving the series a day to follow
+func defaultDueAt(note *Note, now time.Time) error {
+	if note.Recurrence == "" || note.DueAt != nil || note.ScheduledAt != nil {
+		return nil
+	}
+
+	rule, err := ParseRecurrence(note.Recurrence)
+	if err != nil {
+		return err
+	}
+	now = now.Local()
+	first := rule.First(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local))
+	note.DueAt = &first
+	return nil
+}
+
+// nullString stores "" as NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullInt stores 0 as NULL
+func nullInt(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+// RestoreNote re-inserts a deleted note with its original ID and timestamps
+func (s *Storage) RestoreNote(note *Note) error {
+	return s.RestoreNotes([]*Note{note})
+}
+
+// RestoreNotes re-inserts deleted notes in a single transaction, with
+// their embeddings when they have them, as returned by DeleteNotes. The
+// subtasks deleting them moved to the top level move back under them,
+// unless they have been given another parent since.
+func (s *Storage) RestoreNotes(notes []*Note) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		query := `
+			INSERT INTO notes (id, title, content, status, tags, created_at, updated_at, status_changed_at,
+				archived_at, due_at, scheduled_at, priority, recurrence, series_id, parent_id, spawned_from, embedding)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		for _, note := range notes {
+			tagsJSON, err := json.Marshal(note.Tags)
+			if err != nil {
+				return fmt.Errorf("failed to marshal tags: %w", err)
+			}
+
+			var embedding interface{}
+			if len(note.Embedding) > 0 {
+				embeddingJSON, err := json.Marshal(note.Embedding)
+				if err != nil {
+					return fmt.Errorf("failed to marshal embedding: %w", err)
+				}
+				embedding = string(embeddingJSON)
+			}
+
+			_, err = tx.Exec(query, note.ID, note.Title, note.Content, note.Status, string(tagsJSON),
+				note.CreatedAt, note.UpdatedAt, note.StatusChangedAt, note.ArchivedAt, note.DueAt, note.ScheduledAt, note.Priority,
+				nullString(note.Recurrence), nullInt(note.SeriesID), nullInt(note.ParentID), nullInt(note.spawnedFrom), embedding)
+			if err != nil {
+				return fmt.Errorf("failed to restore note %d: %w", note.ID, err)
+			}
+		}
+
+		for _, note := range notes {
+			for _, id := range note.subtasks {
+				_, err := tx.Exec(`UPDATE notes SET parent_id = ? WHERE id = ? AND parent_id IS NULL`, note.ID, id)
+				if err != nil {
+					return fmt.Errorf("failed to restore subtask %d: %w", id, err)
+				}
+			}
+		}
+		return nil
+	})
 }
 
 // GetNote retrieves a note by ID
 func (s *Storage) GetNote(id int) (*Note, error) {
-	query := `SELECT id, content, status, tags, created_at, updated_at FROM notes WHERE id = ?`
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE id = ?`
 	row := s.db.QueryRow(query, id)
 
-	var note Note
-	var tagsJSON string
-	err := row.Scan(&note.ID, &note.Content, &note.Status, &tagsJSON, &note.CreatedAt, &note.UpdatedAt)
+	note, err := scanNote(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("note with ID %d not found", id)
@@ -109,11 +389,7 @@ func (s *Storage) GetNote(id int) (*Note, error) {
 		return nil, fmt.Errorf("failed to scan note: %w", err)
 	}
 
-	if err := json.Unmarshal([]byte(tagsJSON), &note.Tags); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
-	}
-
-	return &note, nil
+	return note, nil
 }
 
 // GetRecentNotes retrieves the most recent notes
@@ -123,7 +399,7 @@ func (s *Storage) GetRecentNotes(limit int) ([]*Note, error) {
 	}
 
 	query := `
-		SELECT id, content, status, tags, created_at, updated_at 
+		SELECT ` + noteColumns + `
 		FROM notes 
 		ORDER BY updated_at DESC 
 		LIMIT ?
@@ -134,61 +410,46 @@ func (s *Storage) GetRecentNotes(limit int) ([]*Note, error) {
 	}
 	defer rows.Close()
 
-	var notes []*Note
-	for rows.Next() {
-		var note Note
-		var tagsJSON string
-		err := rows.Scan(&note.ID, &note.Content, &note.Status, &tagsJSON, &note.CreatedAt, &note.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan note row: %w", err)
-		}
-
-		if err := json.Unmarshal([]byte(tagsJSON), &note.Tags); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
-		}
+	return scanNotes(rows)
+}
 
-		notes = append(notes, &note)
+// GetAllNotes retrieves every note, most recently updated first
+func (s *Storage) GetAllNotes() ([]*Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		ORDER BY updated_at DESC
+	`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
 	}
+	defer rows.Close()
 
-	return notes, nil
+	return scanNotes(rows)
 }
 
 // SearchNotes performs a text-based search on notes
 func (s *Storage) SearchNotes(query string) ([]*Note, error) {
 	searchQuery := `
-		SELECT id, content, status, tags, created_at, updated_at 
+		SELECT ` + noteColumns + `
 		FROM notes 
-		WHERE content LIKE ? 
+		WHERE title LIKE ? OR content LIKE ?
 		ORDER BY updated_at DESC
 	`
 	
-	rows, err := s.db.Query(searchQuery, "%"+query+"%")
+	rows, err := s.db.Query(searchQuery, "%"+query+"%", "%"+query+"%")
 	if err != nil {
 		return nil, fmt.Errorf("failed to search notes: %w", err)
 	}
 	defer rows.Close()
 
-	var notes []*Note
-	for rows.Next() {
-		var note Note
-		var tagsJSON string
-		err := rows.Scan(&note.ID, &note.Content, &note.Status, &tagsJSON, &note.CreatedAt, &note.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan note row: %w", err)
-		}
-
-		if err := json.Unmarshal([]byte(tagsJSON), &note.Tags); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
-		}
-
-		notes = append(notes, &note)
-	}
-
-	return notes, nil
+	return scanNotes(rows)
 }
 
-// UpdateNote updates an existing note
+// UpdateNote updates an existing note, its first line as the title
 func (s *Storage) UpdateNote(id int, content, status string, tags []string) error {
+	title, body := SplitTitle(content)
 	tagsJSON, err := json.Marshal(tags)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tags: %w", err)
@@ -196,10 +457,13 @@ func (s *Storage) UpdateNote(id int, content, status string, tags []string) erro
 
 	query := `
 		UPDATE notes 
-		SET content = ?, status = ?, tags = ?, updated_at = ?
+		SET title = ?, content = ?, tags = ?, updated_at = ?,
+			status_changed_at = CASE WHEN status != ? THEN ? ELSE status_changed_at END,
+			status = ?
 		WHERE id = ?
 	`
-	_, err = s.db.Exec(query, content, status, string(tagsJSON), time.Now(), id)
+	now := time.Now()
+	_, err = s.db.Exec(query, title, body, string(tagsJSON), now, status, now, status, id)
 	if err != nil {
 		return fmt.Errorf("failed to update note: %w", err)
 	}
@@ -207,34 +471,316 @@ func (s *Storage) UpdateNote(id int, content, status string, tags []string) erro
 	return nil
 }
 
-// UpdateNoteStatus updates only the status of a note
+// SaveNote writes back a note's title, content, status, tags, dates, priority
+// and recurrence. Completing a recurring note spawns its next occurrence.
+func (s *Storage) SaveNote(note *Note) error {
+	now := time.Now()
+	if err := defaultDueAt(note, now); err != nil {
+		return err
+	}
+
+	tagsJSON, err := json.Marshal(note.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	return s.withTx(func(tx *sql.Tx) error {
+		from, err := noteStatus(tx, note.ID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE notes
+			SET title = ?, content = ?, tags = ?, due_at = ?, scheduled_at = ?, priority = ?, recurrence = ?,
+				updated_at = ?, status_changed_at = CASE WHEN status != ? THEN ? ELSE status_changed_at END,
+				status = ?
+			WHERE id = ?
+		`
+		_, err = tx.Exec(query, note.Title, note.Content, string(tagsJSON), note.DueAt, note.ScheduledAt, note.Priority,
+			nullString(note.Recurrence), now, note.Status, now, note.Status, note.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+		return s.statusChanged(tx, note.ID, from, note.Status, now)
+	})
+}
+
+// UpdateNoteStatus updates only the status of a note. Completing a
+// recurring note spawns its next occurrence.
 func (s *Storage) UpdateNoteStatus(id int, status string) error {
-	query := `UPDATE notes SET status = ?, updated_at = ? WHERE id = ?`
-	_, err := s.db.Exec(query, status, time.Now(), id)
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.setStatus(tx, id, status, time.Now())
+	})
+}
+
+// noteStatus reads a note's current status in tx
+func noteStatus(tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRow(`SELECT status FROM notes WHERE id = ?`, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("note with ID %d not found", id)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read note status: %w", err)
+	}
+	return status, nil
+}
+
+// setStatus updates a note's status in tx and reacts to the change (see
+// statusChanged)
+func (s *Storage) setStatus(tx *sql.Tx, id int, status string, now time.Time) error {
+	from, err := noteStatus(tx, id)
 	if err != nil {
-		return fmt.Errorf("failed to update note status: %w", err)
+		return err
+	}
+
+	query := `
+		UPDATE notes
+		SET status_changed_at = CASE WHEN status != ? THEN ? ELSE status_changed_at END,
+			status = ?, updated_at = ?
+		WHERE id = ?
+	`
+	if _, err := tx.Exec(query, status, now, status, now, id); err != nil {
+		return fmt.Errorf("failed to update status of note %d: %w", id, err)
+	}
+	return s.statusChanged(tx, id, from, status, now)
+}
+
+// statusChanged runs in tx after a note moves from one status to another.
+// It logs the move, keeps a recurring series going (see recur) and, with
+// the automatic timer on, times the note while it is in doing.
+func (s *Storage) statusChanged(tx *sql.Tx, id int, from, to string, now time.Time) error {
+	if from == to {
+		return nil
+	}
+	if err := logStatusEvent(tx, id, from, to, now); err != nil {
+		return err
+	}
+	if err := recur(tx, id, from, to, now); err != nil {
+		return err
+	}
+
+	if !s.autoTimer {
+		return nil
+	}
+	switch {
+	case to == "doing":
+		_, err := startTimer(tx, id, now)
+		return err
+	case from == "doing":
+		return stopNoteTimer(tx, id, now)
+	}
+	return nil
+}
+
+// UpdateNoteDates sets a note's due and scheduled days; nil clears a date
+func (s *Storage) UpdateNoteDates(id int, dueAt, scheduledAt *time.Time) error {
+	query := `UPDATE notes SET due_at = ?, scheduled_at = ?, updated_at = ? WHERE id = ?`
+	_, err := s.db.Exec(query, dueAt, scheduledAt, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update note dates: %w", err)
 	}
 	return nil
 }
 
 // DeleteNote deletes a note by ID
 func (s *Storage) DeleteNote(id int) error {
-	query := `DELETE FROM notes WHERE id = ?`
-	_, err := s.db.Exec(query, id)
+	_, err := s.DeleteNotes([]int{id})
+	return err
+}
+
+// UpdateNotesStatus sets the status of several notes in a single
+// transaction. statuses maps each note ID to its new status. Completing
+// recurring notes spawns their next occurrences.
+func (s *Storage) UpdateNotesStatus(statuses map[int]string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		now := time.Now()
+		for id, status := range statuses {
+			if err := s.setStatus(tx, id, status, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateNotesTags replaces the tags of several notes in a single
+// transaction. tags maps each note ID to its new tags.
+func (s *Storage) UpdateNotesTags(tags map[int][]string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		now := time.Now()
+		for id, noteTags := range tags {
+			tagsJSON, err := json.Marshal(noteTags)
+			if err != nil {
+				return fmt.Errorf("failed to marshal tags: %w", err)
+			}
+			if _, err := tx.Exec(`UPDATE notes SET tags = ?, updated_at = ? WHERE id = ?`, string(tagsJSON), now, id); err != nil {
+				return fmt.Errorf("failed to update tags of note %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteNotes deletes several notes in a single transaction, stopping a
+// timer running on any of them, and returns them as they were, embeddings
+// included, for RestoreNotes to put back. Their time entries are kept, so
+// a note restored by undo keeps its tracked time. Subtasks of a deleted
+// note move to the top level.
+func (s *Storage) DeleteNotes(ids []int) ([]*Note, error) {
+	var deleted []*Note
+	err := s.withTx(func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, id := range ids {
+			note, err := noteWithEmbedding(tx, id)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read note %d: %w", id, err)
+			}
+			if note.subtasks, err = subtaskIDs(tx, id); err != nil {
+				return err
+			}
+			deleted = append(deleted, note)
+
+			if _, err := tx.Exec(`UPDATE notes SET parent_id = NULL WHERE parent_id = ?`, id); err != nil {
+				return fmt.Errorf("failed to detach subtasks of note %d: %w", id, err)
+			}
+			if _, err := tx.Exec(`DELETE FROM notes WHERE id = ?`, id); err != nil {
+				return fmt.Errorf("failed to delete note %d: %w", id, err)
+			}
+			if err := stopNoteTimer(tx, id, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// subtaskIDs returns the IDs of the note's direct subtasks
+func subtaskIDs(tx *sql.Tx, id int) ([]int, error) {
+	rows, err := tx.Query(`SELECT id FROM notes WHERE parent_id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subtasks of note %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var subtask int
+		if err := rows.Scan(&subtask); err != nil {
+			return nil, fmt.Errorf("failed to scan subtask: %w", err)
+		}
+		ids = append(ids, subtask)
+	}
+	return ids, rows.Err()
+}
+
+// noteWithEmbedding reads a note in tx along with its embedding, if any,
+// and the note it was spawned from
+func noteWithEmbedding(tx *sql.Tx, id int) (*Note, error) {
+	var embeddingJSON sql.NullString
+	var spawnedFrom sql.NullInt64
+	row := tx.QueryRow(`SELECT `+noteColumns+`, embedding, spawned_from FROM notes WHERE id = ?`, id)
+	note, err := scanNote(row, &embeddingJSON, &spawnedFrom)
+	if err != nil {
+		return nil, err
+	}
+	note.spawnedFrom = int(spawnedFrom.Int64)
+
+	if embeddingJSON.String != "" {
+		if err := json.Unmarshal([]byte(embeddingJSON.String), &note.Embedding); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal embedding: %w", err)
+		}
+	}
+	return note, nil
+}
+
+// SetNotesArchived archives or unarchives several notes in a single
+// transaction. Archived notes are kept but no longer shown on the board.
+func (s *Storage) SetNotesArchived(ids []int, archived bool) error {
+	var archivedAt interface{}
+	if archived {
+		archivedAt = time.Now()
+	}
+
+	return s.withTx(func(tx *sql.Tx) error {
+		for _, id := range ids {
+			if _, err := tx.Exec(`UPDATE notes SET archived_at = ? WHERE id = ?`, archivedAt, id); err != nil {
+				return fmt.Errorf("failed to archive note %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetArchivedNotes retrieves the archived notes, most recently archived
+// first
+func (s *Storage) GetArchivedNotes() ([]*Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE archived_at IS NOT NULL
+		ORDER BY archived_at DESC
+	`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archived notes: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNotes(rows)
+}
+
+// withTx runs fn in a transaction, committing when it returns nil and
+// rolling back otherwise
+func (s *Storage) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to delete note: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	return nil
 }
 
-// GetNotesByStatus retrieves notes by status for kanban board
-func (s *Storage) GetNotesByStatus(status string) ([]*Note, error) {
+// Orders for GetNotesByStatus
+const (
+	OrderCreated  = "created"  // Oldest first
+	OrderPriority = "priority" // P0 first, unprioritized last, then oldest first
+)
+
+// GetNotesByStatus retrieves the active (unarchived) notes with a status
+// for the kanban board, in the given order; "" means OrderCreated
+func (s *Storage) GetNotesByStatus(status, order string) ([]*Note, error) {
+	var orderBy string
+	switch order {
+	case "", OrderCreated:
+		orderBy = "created_at ASC"
+	case OrderPriority:
+		orderBy = "priority IS NULL, priority ASC, created_at ASC"
+	default:
+		return nil, fmt.Errorf("unknown order %q (use %s or %s)", order, OrderCreated, OrderPriority)
+	}
+
 	query := `
-		SELECT id, content, status, tags, created_at, updated_at 
+		SELECT ` + noteColumns + `
 		FROM notes 
-		WHERE status = ? 
-		ORDER BY created_at ASC
-	`
+		WHERE status = ? AND archived_at IS NULL
+		ORDER BY ` + orderBy
 	
 	rows, err := s.db.Query(query, status)
 	if err != nil {
@@ -242,23 +788,64 @@ func (s *Storage) GetNotesByStatus(status string) ([]*Note, error) {
 	}
 	defer rows.Close()
 
-	var notes []*Note
-	for rows.Next() {
-		var note Note
-		var tagsJSON string
-		err := rows.Scan(&note.ID, &note.Content, &note.Status, &tagsJSON, &note.CreatedAt, &note.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan note row: %w", err)
-		}
+	return scanNotes(rows)
+}
 
-		if err := json.Unmarshal([]byte(tagsJSON), &note.Tags); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
-		}
+// GetSeries retrieves every note in a recurring series, oldest first
+func (s *Storage) GetSeries(seriesID int) ([]*Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE series_id = ? OR id = ?
+		ORDER BY created_at ASC, id ASC
+	`
 
-		notes = append(notes, &note)
+	rows, err := s.db.Query(query, seriesID, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query series: %w", err)
 	}
+	defer rows.Close()
 
-	return notes, nil
+	return scanNotes(rows)
+}
+
+// GetNotesByDateRange retrieves the active notes due or scheduled on a
+// day from from up to, but not including, to, for the agenda and calendar
+func (s *Storage) GetNotesByDateRange(from, to time.Time) ([]*Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE archived_at IS NULL
+			AND ((due_at >= ? AND due_at < ?) OR (scheduled_at >= ? AND scheduled_at < ?))
+		ORDER BY COALESCE(scheduled_at, due_at) ASC, created_at ASC
+	`
+
+	rows, err := s.db.Query(query, from, to, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes by date: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNotes(rows)
+}
+
+// GetOverdueNotes retrieves the active notes that are not done and were due
+// before a day
+func (s *Storage) GetOverdueNotes(before time.Time) ([]*Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE archived_at IS NULL AND status != 'done' AND due_at < ?
+		ORDER BY due_at ASC, created_at ASC
+	`
+
+	rows, err := s.db.Query(query, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overdue notes: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNotes(rows)
 }
 
 // SaveEmbedding saves an embedding for a note
@@ -280,8 +867,8 @@ func (s *Storage) SaveEmbedding(noteID int, embedding []float64) error {
 // GetNotesWithEmbeddings retrieves all notes that have embeddings
 func (s *Storage) GetNotesWithEmbeddings() ([]*Note, error) {
 	query := `
-		SELECT id, content, status, tags, created_at, updated_at, embedding
-		FROM notes 
+		SELECT ` + noteColumns + `, embedding
+		FROM notes
 		WHERE embedding IS NOT NULL AND embedding != ''
 	`
 	
@@ -293,29 +880,34 @@ func (s *Storage) GetNotesWithEmbeddings() ([]*Note, error) {
 
 	var notes []*Note
 	for rows.Next() {
-		var note Note
-		var tagsJSON, embeddingJSON string
-		err := rows.Scan(&note.ID, &note.Content, &note.Status, &tagsJSON, &note.CreatedAt, &note.UpdatedAt, &embeddingJSON)
+		var embeddingJSON string
+		note, err := scanNote(rows, &embeddingJSON)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan note row: %w", err)
 		}
 
-		if err := json.Unmarshal([]byte(tagsJSON), &note.Tags); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
-		}
-
 		if embeddingJSON != "" {
 			if err := json.Unmarshal([]byte(embeddingJSON), &note.Embedding); err != nil {
 				return nil, fmt.Errorf("failed to unmarshal embedding: %w", err)
 			}
 		}
 
-		notes = append(notes, &note)
+		notes = append(notes, note)
 	}
 
 	return notes, nil
 }
 
+// CountEmbeddings returns how many notes have embeddings
+func (s *Storage) CountEmbeddings() (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notes WHERE embedding IS NOT NULL AND embedding != ''`
+	if err := s.db.QueryRow(query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count embeddings: %w", err)
+	}
+	return count, nil
+}
+
 // migrate creates the necessary database tables
 func (s *Storage) migrate() error {
 	query := `
@@ -339,7 +931,140 @@ func (s *Storage) migrate() error {
 		return fmt.Errorf("failed to create tables: %w", err)
 	}
 
-	return nil
+	// Columns added after the initial schema
+	if _, err := s.addColumn("notes", "status_changed_at", "DATETIME"); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(`UPDATE notes SET status_changed_at = updated_at WHERE status_changed_at IS NULL`); err != nil {
+		return fmt.Errorf("failed to backfill status_changed_at: %w", err)
+	}
+	if _, err := s.addColumn("notes", "archived_at", "DATETIME"); err != nil {
+		return err
+	}
+	if _, err := s.addColumn("notes", "due_at", "DATETIME"); err != nil {
+		return err
+	}
+	if _, err := s.addColumn("notes", "scheduled_at", "DATETIME"); err != nil {
+		return err
+	}
+
+	dateIndexes := `
+		CREATE INDEX IF NOT EXISTS idx_notes_due_at ON notes(due_at);
+		CREATE INDEX IF NOT EXISTS idx_notes_scheduled_at ON notes(scheduled_at);
+	`
+	if _, err := s.db.Exec(dateIndexes); err != nil {
+		return fmt.Errorf("failed to create date indexes: %w", err)
+	}
+
+	// Priorities used to be written as #p0 to #p3 tags; carry the most
+	// urgent one over once, in the transaction adding the column so that a
+	// failed backfill is retried on the next start
+	err = s.withTx(func(tx *sql.Tx) error {
+		added, err := addColumnTx(tx, "notes", "priority", "INTEGER")
+		if err != nil || !added {
+			return err
+		}
+		backfill := `
+			UPDATE notes SET priority = (
+				SELECT MIN(CAST(substr(value, 2) AS INTEGER)) FROM json_each(notes.tags)
+				WHERE value IN ('p0', 'p1', 'p2', 'p3')
+			)
+		`
+		if _, err := tx.Exec(backfill); err != nil {
+			return fmt.Errorf("failed to backfill priority: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.addColumn("notes", "recurrence", "TEXT"); err != nil {
+		return err
+	}
+	if _, err := s.addColumn("notes", "series_id", "INTEGER"); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_notes_series_id ON notes(series_id)`); err != nil {
+		return fmt.Errorf("failed to create series index: %w", err)
+	}
+	if _, err := s.addColumn("notes", "parent_id", "INTEGER"); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_notes_parent_id ON notes(parent_id)`); err != nil {
+		return fmt.Errorf("failed to create parent index: %w", err)
+	}
+	// Deleting a note used to leave its subtasks pointing at it
+	orphans := `UPDATE notes SET parent_id = NULL WHERE parent_id IS NOT NULL AND parent_id NOT IN (SELECT id FROM notes)`
+	if _, err := s.db.Exec(orphans); err != nil {
+		return fmt.Errorf("failed to detach orphaned subtasks: %w", err)
+	}
+	if _, err := s.addColumn("notes", "spawned_from", "INTEGER"); err != nil {
+		return err
+	}
+
+	timeEntries := `
+		CREATE TABLE IF NOT EXISTS time_entries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			note_id INTEGER NOT NULL,
+			started_at DATETIME NOT NULL,
+			ended_at DATETIME
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_time_entries_note_id ON time_entries(note_id);
+		CREATE INDEX IF NOT EXISTS idx_time_entries_ended_at ON time_entries(ended_at);
+	`
+	if _, err := s.db.Exec(timeEntries); err != nil {
+		return fmt.Errorf("failed to create time entries table: %w", err)
+	}
+
+	if err := s.migrateStatusEvents(); err != nil {
+		return err
+	}
+	return s.migrateTitles()
+}
+
+// addColumn adds a column to an existing table unless it is already
+// present, reporting whether it was added
+func (s *Storage) addColumn(table, column, definition string) (bool, error) {
+	var added bool
+	err := s.withTx(func(tx *sql.Tx) (err error) {
+		added, err = addColumnTx(tx, table, column, definition)
+		return err
+	})
+	return added, err
+}
+
+// addColumnTx is addColumn within tx, so that backfilling a new column
+// commits or rolls back together with adding it
+func addColumnTx(tx *sql.Tx, table, column, definition string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			return false, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := tx.Exec(query); err != nil {
+		return false, fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+
+	return true, nil
 }
 
 // getDBPath returns the path to the database file
@@ -352,6 +1077,78 @@ func getDBPath() (string, error) {
 	return filepath.Join(homeDir, ".cheesebox", "cheesebox.db"), nil
 }
 
+// ParseNote builds a note from text written with inline metadata:
+// hashtags, dates (see ParseDates), a priority (see ParsePriority) and a
+// recurrence (see ParseRecurrenceToken). Date, priority and recurrence
+// tokens are removed, and the text is split into a title and body (see
+// SplitTitle).
+func ParseNote(content string, now time.Time) (*Note, error) {
+	content, dueAt, scheduledAt, err := ParseDates(content, now)
+	if err != nil {
+		return nil, err
+	}
+	content, priority := ParsePriority(content)
+	content, recurrence, err := ParseRecurrenceToken(content)
+	if err != nil {
+		return nil, err
+	}
+
+	title, body := SplitTitle(content)
+	return &Note{
+		Title:       title,
+		Content:     body,
+		Tags:        ParseTags(content),
+		DueAt:       dueAt,
+		ScheduledAt: scheduledAt,
+		Priority:    priority,
+		Recurrence:  recurrence,
+	}, nil
+}
+
+// Edit returns a copy of the note with its text, title and body, replaced
+// by content parsed as by ParseNote. Its tags follow the new content,
+// keeping tags set apart from the text, such as from the board; dates, a
+// priority and a recurrence written in it replace the note's, which are
+// kept otherwise.
+func (n *Note) Edit(content string, now time.Time) (*Note, error) {
+	parsed, err := ParseNote(content, now)
+	if err != nil {
+		return nil, err
+	}
+
+	edited := *n
+	edited.Title = parsed.Title
+	edited.Content = parsed.Content
+	edited.Tags = parsed.Tags
+
+	// Tags not written in the old text were set apart from it, so the
+	// new text cannot have removed them
+	written := make(map[string]bool)
+	for _, tag := range append(ParseTags(n.Text()), parsed.Tags...) {
+		written[tag] = true
+	}
+	for _, tag := range n.Tags {
+		if !written[tag] {
+			written[tag] = true
+			edited.Tags = append(edited.Tags, tag)
+		}
+	}
+
+	if parsed.DueAt != nil {
+		edited.DueAt = parsed.DueAt
+	}
+	if parsed.ScheduledAt != nil {
+		edited.ScheduledAt = parsed.ScheduledAt
+	}
+	if parsed.Priority != nil {
+		edited.Priority = parsed.Priority
+	}
+	if parsed.Recurrence != "" {
+		edited.Recurrence = parsed.Recurrence
+	}
+	return &edited, nil
+}
+
 // ParseTags extracts tags from content (words starting with #)
 func ParseTags(content string) []string {
 	words := strings.Fields(content)
@@ -359,6 +1156,9 @@ func ParseTags(content string) []string {
 	for _, word := range words {
 		if strings.HasPrefix(word, "#") && len(word) > 1 {
 			tag := strings.TrimPrefix(word, "#")
+			if strings.HasPrefix(tag, "#") {
+				continue // A markdown heading such as ## Notes
+			}
 			tag = strings.ToLower(tag)
 			// Remove punctuation from end of tag
 			tag = strings.TrimRight(tag, ".,!?;:")
@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TimeEntry is a session of work on a note, recorded by its timer.
+// EndedAt is nil while the timer is running.
+type TimeEntry struct {
+	ID        int        `json:"id"`
+	NoteID    int        `json:"note_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+// Duration returns how long the session lasted, counting up to now while
+// the timer is running
+func (e *TimeEntry) Duration(now time.Time) time.Duration {
+	if e.EndedAt != nil {
+		return e.EndedAt.Sub(e.StartedAt)
+	}
+	return now.Sub(e.StartedAt)
+}
+
+// timeEntryColumns is the column list read by scanTimeEntry
+const timeEntryColumns = `id, note_id, started_at, ended_at`
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanTimeEntry scans a row selected with timeEntryColumns
+func scanTimeEntry(row rowScanner) (*TimeEntry, error) {
+	var entry TimeEntry
+	var endedAt sql.NullTime
+	if err := row.Scan(&entry.ID, &entry.NoteID, &entry.StartedAt, &endedAt); err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		entry.EndedAt = &endedAt.Time
+	}
+	return &entry, nil
+}
+
+// SetAutoTimer turns the automatic timer on or off. When on, a note's
+// timer starts as it moves into doing and stops as it leaves.
+func (s *Storage) SetAutoTimer(enabled bool) {
+	s.autoTimer = enabled
+}
+
+// ActiveTimer returns the running timer, or nil if none is running
+func (s *Storage) ActiveTimer() (*TimeEntry, error) {
+	return activeTimer(s.db)
+}
+
+// StartTimer starts timing work on a note. Only one timer runs at a
+// time, so a timer running on another note is stopped first and
+// returned; starting the timer on the note already being timed changes
+// nothing.
+func (s *Storage) StartTimer(noteID int) (*TimeEntry, error) {
+	var stopped *TimeEntry
+	err := s.withTx(func(tx *sql.Tx) error {
+		if _, err := noteStatus(tx, noteID); err != nil {
+			return err
+		}
+
+		var err error
+		stopped, err = startTimer(tx, noteID, time.Now())
+		return err
+	})
+	return stopped, err
+}
+
+// StopTimer stops the running timer and returns its entry, or nil if no
+// timer was running
+func (s *Storage) StopTimer() (*TimeEntry, error) {
+	var stopped *TimeEntry
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		stopped, err = stopTimer(tx, time.Now())
+		return err
+	})
+	return stopped, err
+}
+
+// GetTimeEntries retrieves the sessions that were running at or after
+// from, oldest first
+func (s *Storage) GetTimeEntries(from time.Time) ([]*TimeEntry, error) {
+	query := `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entries
+		WHERE ended_at IS NULL OR ended_at > ?
+		ORDER BY started_at ASC
+	`
+	rows, err := s.db.Query(query, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetTimeEntryNotes retrieves the notes of the sessions GetTimeEntries
+// returns for from, by ID. Deleted notes keep their sessions but are
+// missing from the map.
+func (s *Storage) GetTimeEntryNotes(from time.Time) (map[int]*Note, error) {
+	query := `
+		SELECT ` + noteColumns + `
+		FROM notes
+		WHERE id IN (
+			SELECT note_id FROM time_entries
+			WHERE ended_at IS NULL OR ended_at > ?
+		)
+	`
+	rows, err := s.db.Query(query, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
+	}
+	defer rows.Close()
+
+	list, err := scanNotes(rows)
+	if err != nil {
+		return nil, err
+	}
+	notes := make(map[int]*Note, len(list))
+	for _, note := range list {
+		notes[note.ID] = note
+	}
+	return notes, nil
+}
+
+// activeTimer returns the running timer, or nil if none is running
+func activeTimer(db queryer) (*TimeEntry, error) {
+	row := db.QueryRow(`SELECT ` + timeEntryColumns + ` FROM time_entries WHERE ended_at IS NULL`)
+	entry, err := scanTimeEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the active timer: %w", err)
+	}
+	return entry, nil
+}
+
+// startTimer starts a note's timer in tx as described by StartTimer
+func startTimer(tx *sql.Tx, noteID int, now time.Time) (*TimeEntry, error) {
+	active, err := activeTimer(tx)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil && active.NoteID == noteID {
+		return nil, nil
+	}
+
+	stopped, err := stopTimer(tx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO time_entries (note_id, started_at) VALUES (?, ?)`, noteID, now); err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+	return stopped, nil
+}
+
+// stopTimer stops the running timer in tx, returning its entry or nil if
+// none was running
+func stopTimer(tx *sql.Tx, now time.Time) (*TimeEntry, error) {
+	active, err := activeTimer(tx)
+	if err != nil || active == nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE time_entries SET ended_at = ? WHERE id = ?`, now, active.ID); err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	active.EndedAt = &now
+	return active, nil
+}
+
+// stopNoteTimer stops the running timer in tx if it is timing the note
+func stopNoteTimer(tx *sql.Tx, noteID int, now time.Time) error {
+	_, err := tx.Exec(`UPDATE time_entries SET ended_at = ? WHERE ended_at IS NULL AND note_id = ?`, now, noteID)
+	if err != nil {
+		return fmt.Errorf("failed to stop timer: %w", err)
+	}
+	return nil
+}
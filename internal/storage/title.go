@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SplitTitle splits note text into its title, the first line that is not
+// blank, and its body, the lines after it without surrounding blank lines
+func SplitTitle(text string) (title, body string) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return "", ""
+	}
+
+	title, lines = strings.TrimSpace(lines[0]), lines[1:]
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	return title, strings.TrimRight(strings.Join(lines, "\n"), " \t\n")
+}
+
+// JoinNote joins a title and body into note text, separated by a blank
+// line, as read back by SplitTitle
+func JoinNote(title, body string) string {
+	if body == "" {
+		return title
+	}
+	return title + "\n\n" + body
+}
+
+// Text returns the note's full text: its title, then its body
+func (n *Note) Text() string {
+	return JoinNote(n.Title, n.Content)
+}
+
+// migrateTitles adds the title column, splitting the first line of every
+// existing note out of its content as its title. Both happen in one
+// transaction, so a failed backfill is retried on the next start.
+func (s *Storage) migrateTitles() error {
+	return s.withTx(func(tx *sql.Tx) error {
+		added, err := addColumnTx(tx, "notes", "title", "TEXT NOT NULL DEFAULT ''")
+		if err != nil || !added {
+			return err
+		}
+
+		rows, err := tx.Query(`SELECT id, content FROM notes`)
+		if err != nil {
+			return fmt.Errorf("failed to read notes: %w", err)
+		}
+		contents := make(map[int]string)
+		for rows.Next() {
+			var id int
+			var content string
+			if err := rows.Scan(&id, &content); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan note: %w", err)
+			}
+			contents[id] = content
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read notes: %w", err)
+		}
+
+		for id, content := range contents {
+			title, body := SplitTitle(content)
+			if _, err := tx.Exec(`UPDATE notes SET title = ?, content = ? WHERE id = ?`, title, body, id); err != nil {
+				return fmt.Errorf("failed to set title of note %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+package storage
+
+import "testing"
+
+func TestSplitTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		title string
+		body  string
+	}{
+		{"empty", "", "", ""},
+		{"blank", " \n\t\n", "", ""},
+		{"title only", "Buy milk", "Buy milk", ""},
+		{"title and body", "Release\n\n- [ ] Tag the build", "Release", "- [ ] Tag the build"},
+		{"no blank line", "Release\nTag the build", "Release", "Tag the build"},
+		{"leading blank lines", "\n\n  Release  \n\nNotes", "Release", "Notes"},
+		{"trailing blank lines", "Release\n\nNotes\n\n \n", "Release", "Notes"},
+		{"body indentation kept", "Snippet\n\n    go test ./...\n  done", "Snippet", "    go test ./...\n  done"},
+		{"inner blank lines kept", "Plan\n\nFirst\n\n\nSecond", "Plan", "First\n\n\nSecond"},
+		{"windows line endings", "Plan\r\n\r\nFirst\r\nSecond", "Plan", "First\nSecond"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body := SplitTitle(tt.text)
+			if title != tt.title || body != tt.body {
+				t.Errorf("SplitTitle(%q) = %q, %q, want %q, %q", tt.text, title, body, tt.title, tt.body)
+			}
+		})
+	}
+}
+
+func TestJoinNote(t *testing.T) {
+	if got := JoinNote("Buy milk", ""); got != "Buy milk" {
+		t.Errorf("JoinNote without a body = %q, want %q", got, "Buy milk")
+	}
+	if got := JoinNote("Release", "- [ ] Tag"); got != "Release\n\n- [ ] Tag" {
+		t.Errorf("JoinNote = %q, want %q", got, "Release\n\n- [ ] Tag")
+	}
+
+	for _, note := range []struct{ title, body string }{
+		{"Buy milk", ""},
+		{"Release", "- [ ] Tag the build\n\n- [ ] Publish"},
+	} {
+		title, body := SplitTitle(JoinNote(note.title, note.body))
+		if title != note.title || body != note.body {
+			t.Errorf("SplitTitle(JoinNote(%q, %q)) = %q, %q", note.title, note.body, title, body)
+		}
+	}
+}
+
+func TestParseNoteKeepsBodyOutOfTitle(t *testing.T) {
+	tests := []struct {
+		text  string
+		title string
+		body  string
+	}{
+		{"Plan the offsite\ndue:fri Book the venue", "Plan the offsite", "Book the venue"},
+		{"Database is down\n!!! Page the on-call", "Database is down", "Page the on-call"},
+		{"Water the plants\nevery:2d Ferns first", "Water the plants", "Ferns first"},
+		{"Release due:fri !p1\n\n- [ ] Tag the build #release", "Release", "- [ ] Tag the build #release"},
+	}
+
+	for _, tt := range tests {
+		note, err := ParseNote(tt.text, testNow)
+		if err != nil {
+			t.Fatalf("ParseNote(%q) error: %v", tt.text, err)
+		}
+		if note.Title != tt.title || note.Content != tt.body {
+			t.Errorf("ParseNote(%q) = %q, %q, want %q, %q", tt.text, note.Title, note.Content, tt.title, tt.body)
+		}
+	}
+}
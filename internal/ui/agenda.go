@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cheesebox/internal/storage"
+)
+
+// dayFormat is the layout of the keys used to group notes by day
+const dayFormat = "2006-01-02"
+
+// agendaItem is a note shown on one of its days: the day it is scheduled
+// for or the day it is due. A note with both dates can appear twice.
+type agendaItem struct {
+	note      *storage.Note
+	scheduled bool // Shown on its scheduled day rather than its due day
+}
+
+// date returns the day the item is shown on
+func (i agendaItem) date() time.Time {
+	if i.scheduled {
+		return *i.note.ScheduledAt
+	}
+	return *i.note.DueAt
+}
+
+// kind names the date the item is shown by
+func (i agendaItem) kind() string {
+	if i.scheduled {
+		return "scheduled"
+	}
+	return "due"
+}
+
+// overdue reports whether the item is due before today and not done
+func (i agendaItem) overdue(today time.Time) bool {
+	return !i.scheduled && i.note.IsOverdue(today)
+}
+
+// startOfDay returns local midnight at the start of t's day
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// addDays returns the local midnight n days after day, staying on
+// midnight across daylight saving changes
+func addDays(day time.Time, n int) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d+n, 0, 0, 0, 0, time.Local)
+}
+
+// groupByDay groups notes into agenda items keyed by dayFormat. A note
+// due and scheduled on the same day is listed once, as scheduled.
+func groupByDay(notes []*storage.Note) map[string][]agendaItem {
+	days := make(map[string][]agendaItem)
+	for _, note := range notes {
+		var scheduledDay string
+		if note.ScheduledAt != nil {
+			scheduledDay = note.ScheduledAt.Local().Format(dayFormat)
+			days[scheduledDay] = append(days[scheduledDay], agendaItem{note: note, scheduled: true})
+		}
+		if note.DueAt != nil {
+			if day := note.DueAt.Local().Format(dayFormat); day != scheduledDay {
+				days[day] = append(days[day], agendaItem{note: note})
+			}
+		}
+	}
+	return days
+}
+
+// RenderAgenda renders overdue notes, then the notes due or scheduled on
+// each day from today through the following days
+func RenderAgenda(overdue, upcoming []*storage.Note, today time.Time, days int) string {
+	today = startOfDay(today)
+
+	var output strings.Builder
+	output.WriteString(active.TitleStyle.Render("📅 Agenda"))
+	output.WriteString("\n")
+
+	if len(overdue) > 0 {
+		output.WriteString(active.ErrorStyle.Render(fmt.Sprintf("⚠️  Overdue (%d)", len(overdue))))
+		output.WriteString("\n")
+		for _, note := range overdue {
+			output.WriteString(renderAgendaItem(agendaItem{note: note}, today))
+		}
+		output.WriteString("\n")
+	}
+
+	byDay := groupByDay(upcoming)
+	shown := 0
+	for i := 0; i <= days; i++ {
+		day := addDays(today, i)
+		items := byDay[day.Format(dayFormat)]
+		if len(items) == 0 && i > 0 {
+			continue
+		}
+
+		output.WriteString(active.LabelStyle.Render(dayLabel(day, today)))
+		output.WriteString("\n")
+		if len(items) == 0 {
+			output.WriteString(active.MutedStyle.Render("  Nothing due or scheduled"))
+			output.WriteString("\n")
+		}
+		for _, item := range items {
+			output.WriteString(renderAgendaItem(item, today))
+			shown++
+		}
+		output.WriteString("\n")
+	}
+
+	if shown == 0 {
+		output.WriteString(active.MutedStyle.Render(fmt.Sprintf("Nothing due or scheduled in the next %d days", days)))
+	} else {
+		output.WriteString(active.MutedStyle.Render(fmt.Sprintf("Total: %d overdue, %d in the next %d days", len(overdue), shown, days)))
+	}
+
+	return output.String()
+}
+
+// renderAgendaItem renders one agenda line: ID, status, content and
+// which of its dates puts the note on the agenda
+func renderAgendaItem(item agendaItem, today time.Time) string {
+	note := item.note
+	content := truncate(note.Title, 60)
+
+	style := active.ContentStyle
+	when := item.kind()
+	if item.overdue(today) {
+		style = active.ErrorStyle
+		when += " " + relativeDay(item.date(), today)
+	}
+
+	line := fmt.Sprintf("  %-5s %s %s", fmt.Sprintf("#%d", note.ID), renderStatus(note.Status), style.Render(content))
+	return line + active.MutedStyle.Render(" · "+when) + "\n"
+}
+
+// relativeDay names a day for inline use: "today", "tomorrow",
+// "yesterday", or the weekday and date
+func relativeDay(day, today time.Time) string {
+	switch startOfDay(day).Format(dayFormat) {
+	case today.Format(dayFormat):
+		return "today"
+	case addDays(today, 1).Format(dayFormat):
+		return "tomorrow"
+	case addDays(today, -1).Format(dayFormat):
+		return "yesterday"
+	}
+	if day.Year() != today.Year() {
+		return day.Format("Mon Jan 2, 2006")
+	}
+	return day.Format("Mon Jan 2")
+}
+
+// noteDates describes a note's due and scheduled days, e.g. "due
+// tomorrow • scheduled today"
+func noteDates(note *storage.Note, today time.Time) []string {
+	var dates []string
+	if note.DueAt != nil {
+		dates = append(dates, "due "+relativeDay(*note.DueAt, today))
+	}
+	if note.ScheduledAt != nil {
+		dates = append(dates, "scheduled "+relativeDay(*note.ScheduledAt, today))
+	}
+	return dates
+}
+
+// dayLabel names a day relative to today: "Today", "Tomorrow",
+// "Yesterday", or the weekday and date
+func dayLabel(day, today time.Time) string {
+	switch startOfDay(day).Format(dayFormat) {
+	case today.Format(dayFormat):
+		return "Today · " + day.Format("Mon Jan 2")
+	case addDays(today, 1).Format(dayFormat):
+		return "Tomorrow · " + day.Format("Mon Jan 2")
+	case addDays(today, -1).Format(dayFormat):
+		return "Yesterday"
+	}
+	if day.Year() != today.Year() {
+		return day.Format("Mon Jan 2, 2006")
+	}
+	return day.Format("Mon Jan 2")
+}
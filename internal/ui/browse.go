@@ -0,0 +1,746 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/termenv"
+	"cheesebox/internal/search"
+	"cheesebox/internal/storage"
+)
+
+// Browser sort orders
+const (
+	sortUpdated  = "updated"  // Most recently updated first
+	sortCreated  = "created"  // Newest first
+	sortStatus   = "status"   // Board order, then most recently updated
+	sortPriority = "priority" // Most urgent first, then most recently updated
+)
+
+// browseSorts is the order the sort key cycles through
+var browseSorts = []string{sortUpdated, sortCreated, sortStatus, sortPriority}
+
+// minPreviewWidth is the narrowest terminal that shows the preview pane
+// beside the list
+const minPreviewWidth = 80
+
+// BrowseModel is a full-screen list of every note with fuzzy filtering,
+// sorting, a preview pane and actions on the selected note
+type BrowseModel struct {
+	storage     *storage.Storage
+	notes       []*storage.Note // Every note, as loaded
+	rows        []*storage.Note // Notes shown: filtered and sorted
+	selected    int             // Index within rows
+	offset      int             // First row scrolled into view
+	width       int
+	height      int
+	quitting    bool
+	prompt             // Open input or confirmation, if any
+	statusMsg   string // Feedback shown above the instructions
+	focusNoteID int    // Note to select after the next reload
+
+	filter      string
+	sortBy      string
+	reverse     bool
+	showPreview bool
+
+	// Related view: the note whose related notes are listed, and those
+	// notes in order of similarity
+	relatedTo *storage.Note
+	related   []*storage.Note
+
+	keys browseKeyMap
+}
+
+// BrowseOptions configures the note browser
+type BrowseOptions struct {
+	// Sort is the initial sort order: updated, created, status or priority
+	Sort string
+
+	// Query is the initial filter
+	Query string
+}
+
+// browseKeyMap holds the browser's key bindings
+type browseKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+	Filter   key.Binding
+	Clear    key.Binding
+	Sort     key.Binding
+	Reverse  key.Binding
+	Preview  key.Binding
+	Edit     key.Binding
+	Delete   key.Binding
+	Todo     key.Binding
+	Doing    key.Binding
+	Done     key.Binding
+	Related  key.Binding
+	Archive  key.Binding
+	CopyID   key.Binding
+	Quit     key.Binding
+}
+
+// defaultBrowseKeyMap returns the browser's key bindings
+func defaultBrowseKeyMap() browseKeyMap {
+	return browseKeyMap{
+		Up:       binding("Up", "up", "k"),
+		Down:     binding("Down", "down", "j"),
+		PageUp:   binding("Page up", "pgup", "ctrl+u"),
+		PageDown: binding("Page down", "pgdown", "ctrl+d"),
+		Top:      binding("First", "g", "home"),
+		Bottom:   binding("Last", "G", "end"),
+		Filter:   binding("Filter", "/"),
+		Clear:    binding("Back/clear filter", "esc"),
+		Sort:     binding("Sort", "o"),
+		Reverse:  binding("Reverse", "O"),
+		Preview:  binding("Preview", "p"),
+		Edit:     binding("Edit", "e"),
+		Delete:   binding("Delete", "d"),
+		Todo:     binding("TODO", "1"),
+		Doing:    binding("DOING", "2"),
+		Done:     binding("DONE", "3"),
+		Related:  binding("Related notes", "r"),
+		Archive:  binding("Archive/unarchive", "A"),
+		CopyID:   binding("Copy ID", "y"),
+		Quit:     binding("Quit", "q", "ctrl+c"),
+	}
+}
+
+// all returns every binding in the order the instructions list them
+func (k browseKeyMap) all() []key.Binding {
+	return []key.Binding{
+		k.Up, k.Down, k.Filter, k.Clear, k.Sort, k.Reverse, k.Preview, k.Edit, k.Delete,
+		k.Todo, k.Doing, k.Done, k.Related, k.Archive, k.CopyID, k.Quit,
+	}
+}
+
+// relatedMsg carries the notes related to a note
+type relatedMsg struct {
+	note  *storage.Note
+	notes []*storage.Note
+}
+
+// StartBrowse initializes and starts the note browser
+func StartBrowse(storage *storage.Storage, opts BrowseOptions) error {
+	sortBy := opts.Sort
+	if sortBy == "" {
+		sortBy = sortUpdated
+	}
+	if !validSort(sortBy) {
+		return fmt.Errorf("unknown sort %q (use %s)", sortBy, strings.Join(browseSorts, ", "))
+	}
+
+	model := &BrowseModel{
+		storage:     storage,
+		sortBy:      sortBy,
+		filter:      opts.Query,
+		showPreview: true,
+		keys:        defaultBrowseKeyMap(),
+	}
+
+	if err := model.loadNotes(); err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// validSort reports whether sortBy is one of browseSorts
+func validSort(sortBy string) bool {
+	for _, s := range browseSorts {
+		if s == sortBy {
+			return true
+		}
+	}
+	return false
+}
+
+// Init implements tea.Model
+func (m *BrowseModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *BrowseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToSelection()
+		return m, nil
+
+	case refreshMsg:
+		if msg.focusNoteID != 0 {
+			m.focusNoteID = msg.focusNoteID
+		}
+		if msg.status != "" {
+			m.statusMsg = msg.status
+		}
+		if err := m.loadNotes(); err != nil {
+			m.statusMsg = fmt.Sprintf("Error loading notes: %v", err)
+		}
+		return m, nil
+
+	case relatedMsg:
+		if len(msg.notes) == 0 {
+			m.statusMsg = fmt.Sprintf("No related notes for #%d", msg.note.ID)
+			return m, nil
+		}
+		m.relatedTo = msg.note
+		m.related = msg.notes
+		m.selected = 0
+		m.statusMsg = ""
+		m.applyRows()
+		return m, nil
+
+	case error:
+		m.statusMsg = fmt.Sprintf("Error: %v", msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.promptOpen() {
+			var cmd tea.Cmd
+			cmd, m.quitting = m.handlePromptKey(msg, &m.statusMsg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, m.keys.Up):
+			m.selectRow(-1)
+
+		case key.Matches(msg, m.keys.Down):
+			m.selectRow(1)
+
+		case key.Matches(msg, m.keys.PageUp):
+			m.selectRow(-m.listRows())
+
+		case key.Matches(msg, m.keys.PageDown):
+			m.selectRow(m.listRows())
+
+		case key.Matches(msg, m.keys.Top):
+			m.selectRow(-m.selected)
+
+		case key.Matches(msg, m.keys.Bottom):
+			m.selectRow(len(m.rows))
+
+		case key.Matches(msg, m.keys.Filter):
+			return m, m.startFilter()
+
+		case key.Matches(msg, m.keys.Clear):
+			m.clear()
+
+		case key.Matches(msg, m.keys.Sort):
+			for i, s := range browseSorts {
+				if s == m.sortBy {
+					m.sortBy = browseSorts[(i+1)%len(browseSorts)]
+					break
+				}
+			}
+			m.keepSelection()
+			m.applyRows()
+
+		case key.Matches(msg, m.keys.Reverse):
+			m.reverse = !m.reverse
+			m.keepSelection()
+			m.applyRows()
+
+		case key.Matches(msg, m.keys.Preview):
+			m.showPreview = !m.showPreview
+
+		case key.Matches(msg, m.keys.Edit):
+			return m, m.startEditNote()
+
+		case key.Matches(msg, m.keys.Delete):
+			m.confirmDeleteNote()
+
+		case key.Matches(msg, m.keys.Todo):
+			return m, m.setStatus("todo")
+
+		case key.Matches(msg, m.keys.Doing):
+			return m, m.setStatus("doing")
+
+		case key.Matches(msg, m.keys.Done):
+			return m, m.setStatus("done")
+
+		case key.Matches(msg, m.keys.Related):
+			return m, m.findRelated()
+
+		case key.Matches(msg, m.keys.Archive):
+			return m, m.toggleArchived()
+
+		case key.Matches(msg, m.keys.CopyID):
+			if note := m.selectedNote(); note != nil {
+				// OSC 52 asks the terminal to set the clipboard, which also
+				// works over SSH
+				termenv.Copy(fmt.Sprintf("%d", note.ID))
+				m.statusMsg = fmt.Sprintf("Copied #%d to the clipboard", note.ID)
+			}
+		}
+		return m, nil
+	}
+
+	// Keep the text input's cursor blinking
+	return m, m.updatePrompt(msg)
+}
+
+// View implements tea.Model
+func (m *BrowseModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	listWidth, previewWidth := m.widths()
+	list := m.renderList(listWidth)
+	if previewWidth > 0 {
+		preview := lipgloss.NewStyle().MaxHeight(lipgloss.Height(list)).
+			Render(renderNoteDetail(m.selectedNote(), previewWidth))
+		list = lipgloss.JoinHorizontal(lipgloss.Top, list, " ", preview)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.renderTitle(),
+		list,
+		m.renderStatusLine(),
+		"",
+		m.renderInstructions(),
+	)
+}
+
+// loadNotes loads every note from storage, keeping the selected note
+// selected
+func (m *BrowseModel) loadNotes() error {
+	notes, err := m.storage.GetAllNotes()
+	if err != nil {
+		return err
+	}
+	m.notes = notes
+
+	// Drop deleted notes from the related view
+	if m.relatedTo != nil {
+		byID := make(map[int]*storage.Note, len(notes))
+		for _, note := range notes {
+			byID[note.ID] = note
+		}
+		var related []*storage.Note
+		for _, note := range m.related {
+			if current, ok := byID[note.ID]; ok {
+				related = append(related, current)
+			}
+		}
+		m.related = related
+	}
+
+	if m.focusNoteID == 0 {
+		m.keepSelection()
+	}
+	m.applyRows()
+	return nil
+}
+
+// keepSelection makes the next applyRows select the currently selected note
+func (m *BrowseModel) keepSelection() {
+	if note := m.selectedNote(); note != nil {
+		m.focusNoteID = note.ID
+	}
+}
+
+// applyRows filters and sorts the notes into rows, following focusNoteID
+// to its new row when one is set
+func (m *BrowseModel) applyRows() {
+	var notes []*storage.Note
+	if m.relatedTo != nil {
+		notes = m.related
+	} else {
+		notes = sortNotes(m.notes, m.sortBy, m.reverse)
+	}
+
+	if strings.TrimSpace(m.filter) == "" {
+		m.rows = notes
+	} else {
+		// Best matches first, keeping the sort order among equal scores
+		scores := make(map[int]int)
+		var matched []*storage.Note
+		for _, note := range notes {
+			if score, ok := fuzzyMatch(m.filter, noteSearchText(note)); ok {
+				scores[note.ID] = score
+				matched = append(matched, note)
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool {
+			return scores[matched[i].ID] > scores[matched[j].ID]
+		})
+		m.rows = matched
+	}
+
+	if m.focusNoteID != 0 {
+		for i, note := range m.rows {
+			if note.ID == m.focusNoteID {
+				m.selected = i
+			}
+		}
+		m.focusNoteID = 0
+	}
+	m.selectRow(0)
+}
+
+// sortNotes returns a copy of notes in the given order
+func sortNotes(notes []*storage.Note, sortBy string, reverse bool) []*storage.Note {
+	sorted := append([]*storage.Note(nil), notes...)
+
+	statusRank := make(map[string]int)
+	for i, status := range storage.Statuses {
+		statusRank[status] = i
+	}
+
+	less := func(a, b *storage.Note) bool {
+		switch sortBy {
+		case sortCreated:
+			return a.CreatedAt.After(b.CreatedAt)
+		case sortStatus:
+			if statusRank[a.Status] != statusRank[b.Status] {
+				return statusRank[a.Status] < statusRank[b.Status]
+			}
+		case sortPriority:
+			if a.PriorityRank() != b.PriorityRank() {
+				return a.PriorityRank() < b.PriorityRank()
+			}
+		}
+		return a.UpdatedAt.After(b.UpdatedAt)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if reverse {
+			return less(sorted[j], sorted[i])
+		}
+		return less(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// noteSearchText is the text the filter matches: ID, status, title, body
+// and tags
+func noteSearchText(note *storage.Note) string {
+	text := fmt.Sprintf("#%d %s %s %s", note.ID, note.Status, note.Title, note.Content)
+	for _, tag := range note.Tags {
+		text += " #" + tag
+	}
+	return text
+}
+
+// selectedNote returns the selected note, if any
+func (m *BrowseModel) selectedNote() *storage.Note {
+	if m.selected < 0 || m.selected >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.selected]
+}
+
+// selectRow moves the selection by delta rows, clamped to the list
+func (m *BrowseModel) selectRow(delta int) {
+	m.selected += delta
+	if m.selected >= len(m.rows) {
+		m.selected = len(m.rows) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	m.scrollToSelection()
+}
+
+// scrollToSelection keeps the selected row in view
+func (m *BrowseModel) scrollToSelection() {
+	m.offset = scrollOffset(m.offset, m.selected, m.listRows(), len(m.rows))
+}
+
+// clear leaves the related view, or clears the filter
+func (m *BrowseModel) clear() {
+	switch {
+	case m.relatedTo != nil:
+		m.focusNoteID = m.relatedTo.ID
+		m.relatedTo = nil
+		m.related = nil
+		m.statusMsg = ""
+		m.applyRows()
+	case m.filter != "":
+		m.keepSelection()
+		m.filter = ""
+		m.statusMsg = "Filter cleared"
+		m.applyRows()
+	}
+}
+
+// startFilter opens the filter input, narrowing the list as you type
+func (m *BrowseModel) startFilter() tea.Cmd {
+	cmd := m.openInput("Filter:", m.filter, func(value string) tea.Cmd {
+		m.statusMsg = ""
+		return nil
+	})
+	m.input.onChange = func(value string) tea.Cmd {
+		m.filter = value
+		m.selected = 0
+		m.applyRows()
+		return nil
+	}
+	m.input.cancel = func() {
+		m.filter = ""
+		m.statusMsg = "Filter cleared"
+		m.applyRows()
+	}
+	return cmd
+}
+
+// startEditNote prompts for the selected note's new content
+func (m *BrowseModel) startEditNote() tea.Cmd {
+	note := m.selectedNote()
+	if note == nil {
+		return nil
+	}
+
+	// The prompt edits the title; the body is kept
+	return m.openInput(fmt.Sprintf("Edit #%d:", note.ID), note.Title, func(title string) tea.Cmd {
+		edited, err := note.Edit(storage.JoinNote(title, note.Content), time.Now())
+		if err != nil {
+			m.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		if edited.Title == "" || title == note.Title {
+			m.statusMsg = "Unchanged"
+			return nil
+		}
+		return func() tea.Msg {
+			if err := m.storage.SaveNote(edited); err != nil {
+				return err
+			}
+			return refreshMsg{focusNoteID: note.ID, status: fmt.Sprintf("Updated #%d", note.ID)}
+		}
+	})
+}
+
+// confirmDeleteNote asks before deleting the selected note
+func (m *BrowseModel) confirmDeleteNote() {
+	note := m.selectedNote()
+	if note == nil {
+		return
+	}
+
+	m.confirm = &confirmation{
+		prompt: fmt.Sprintf("Delete #%d?", note.ID),
+		action: func() tea.Cmd {
+			return func() tea.Msg {
+				if err := m.storage.DeleteNote(note.ID); err != nil {
+					return err
+				}
+				return refreshMsg{status: fmt.Sprintf("Deleted #%d", note.ID)}
+			}
+		},
+	}
+}
+
+// setStatus changes the selected note's status
+func (m *BrowseModel) setStatus(status string) tea.Cmd {
+	note := m.selectedNote()
+	if note == nil || note.Status == status {
+		return nil
+	}
+
+	return func() tea.Msg {
+		if err := m.storage.UpdateNoteStatus(note.ID, status); err != nil {
+			return err
+		}
+		return refreshMsg{focusNoteID: note.ID, status: fmt.Sprintf("Moved #%d to %s", note.ID, strings.ToUpper(status))}
+	}
+}
+
+// toggleArchived archives the selected note, or puts it back on the
+// board if it is archived
+func (m *BrowseModel) toggleArchived() tea.Cmd {
+	note := m.selectedNote()
+	if note == nil {
+		return nil
+	}
+
+	archived := note.ArchivedAt == nil
+	return func() tea.Msg {
+		if err := m.storage.SetNotesArchived([]int{note.ID}, archived); err != nil {
+			return err
+		}
+		status := fmt.Sprintf("Unarchived #%d", note.ID)
+		if archived {
+			status = fmt.Sprintf("Archived #%d", note.ID)
+		}
+		return refreshMsg{focusNoteID: note.ID, status: status}
+	}
+}
+
+// findRelated looks up the notes related to the selected note
+func (m *BrowseModel) findRelated() tea.Cmd {
+	note := m.selectedNote()
+	if note == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		notes, err := search.RelatedNotes(m.storage, note, 20)
+		if err != nil {
+			return err
+		}
+		return relatedMsg{note: note, notes: notes}
+	}
+}
+
+// widths splits the terminal between the list and the preview pane,
+// which is hidden on narrow terminals
+func (m *BrowseModel) widths() (int, int) {
+	width := m.width
+	if width == 0 {
+		width = 100
+	}
+	if !m.showPreview || width < minPreviewWidth {
+		return width, 0
+	}
+	preview := width * 2 / 5
+	return width - preview - 1, preview
+}
+
+// listRows returns how many notes fit in the list
+func (m *BrowseModel) listRows() int {
+	if m.height == 0 {
+		return defaultColumnRows
+	}
+
+	// Title, column header, status line, blank line and instructions
+	chrome := lipgloss.Height(m.renderTitle()) + 1 + 1 + 1 + lipgloss.Height(m.renderInstructions())
+	rows := m.height - chrome
+	if rows < minColumnRows {
+		rows = minColumnRows
+	}
+	return rows
+}
+
+// renderTitle renders the title with the current view and sort order
+func (m *BrowseModel) renderTitle() string {
+	title := "🗂️  Browse Notes"
+	if m.relatedTo != nil {
+		title = fmt.Sprintf("🔗 Related to #%d", m.relatedTo.ID)
+	}
+
+	order := "by similarity"
+	if m.relatedTo == nil {
+		order = "sorted by " + m.sortBy
+		if m.reverse {
+			order += ", reversed"
+		}
+	}
+	if strings.TrimSpace(m.filter) != "" {
+		order += ", best matches first"
+	}
+
+	count := fmt.Sprintf("%d notes", len(m.rows))
+	if len(m.rows) == 1 {
+		count = "1 note"
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, active.TitleStyle.Render(title), "  ", active.MutedStyle.Render(count+" • "+order))
+}
+
+// renderList renders the column header and the visible rows, width wide
+func (m *BrowseModel) renderList(width int) string {
+	idWidth := 2
+	for _, note := range m.rows {
+		if w := len(fmt.Sprintf("#%d", note.ID)); w > idWidth {
+			idWidth = w
+		}
+	}
+
+	lines := []string{active.LabelStyle.Render(fmt.Sprintf(" %-*s %-6s %-8s %s", idWidth, "ID", "STATUS", "UPDATED", "NOTE"))}
+
+	rows := m.listRows()
+	end := m.offset + rows
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+	for i := m.offset; i < end; i++ {
+		lines = append(lines, m.renderRow(m.rows[i], i == m.selected, idWidth, width))
+	}
+	if len(m.rows) == 0 {
+		lines = append(lines, active.MutedStyle.Render(" No notes match"))
+	}
+	for len(lines) < rows+1 {
+		lines = append(lines, "")
+	}
+
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
+
+// renderRow renders one note as a list row
+func (m *BrowseModel) renderRow(note *storage.Note, selected bool, idWidth, width int) string {
+	content := note.Title
+	if note.ArchivedAt != nil {
+		content = "[archived] " + content
+	}
+
+	id := fmt.Sprintf("%-*s", idWidth, fmt.Sprintf("#%d", note.ID))
+	status := fmt.Sprintf("%-6s", strings.ToUpper(note.Status))
+	updated := runewidth.FillRight(formatTime(note.UpdatedAt), 8)
+	text := truncate(content, width-idWidth-6-8-5)
+
+	if selected {
+		return active.HighlightStyle.Render(truncate(fmt.Sprintf("%s %s %s %s", id, status, updated, text), width-2))
+	}
+	textStyle := active.ContentStyle
+	if note.IsOverdue(time.Now()) {
+		textStyle = active.ErrorStyle
+	}
+
+	return " " + active.ContentStyle.Render(id) + " " + statusStyle(note.Status).Render(status) + " " + active.MutedStyle.Render(updated) + " " + textStyle.Render(text)
+}
+
+// renderStatusLine renders the active prompt, the filter or the last
+// status message
+func (m *BrowseModel) renderStatusLine() string {
+	if prompt := m.promptView(); prompt != "" {
+		return prompt
+	}
+
+	var parts []string
+	if m.filter != "" {
+		parts = append(parts, active.LabelStyle.Render("Filter:")+" "+m.filter)
+	}
+	if m.statusMsg != "" {
+		parts = append(parts, active.MutedStyle.Render(m.statusMsg))
+	}
+	return strings.Join(parts, active.MutedStyle.Render(" • "))
+}
+
+// renderInstructions renders the key bindings, wrapped to the terminal
+func (m *BrowseModel) renderInstructions() string {
+	width := m.width
+	if width == 0 {
+		width = 100
+	}
+
+	var instructions []string
+	for _, b := range m.keys.all() {
+		instructions = append(instructions, b.Help().Key+": "+b.Help().Desc)
+	}
+	return active.MutedStyle.Render(wordwrap.String(strings.Join(instructions, " • "), width))
+}
@@ -0,0 +1,612 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+	"cheesebox/internal/storage"
+)
+
+// dayPaneRows is how many of the selected day's notes are listed below
+// the month grid
+const dayPaneRows = 4
+
+// CalendarModel is a month grid of the notes due or scheduled on each
+// day. Days are navigable, and moving a card to another day changes the
+// date it is shown by.
+type CalendarModel struct {
+	storage     *storage.Storage
+	month       time.Time // First day of the month shown
+	cursor      time.Time // Selected day
+	days        map[string][]agendaItem
+	selected    int // Index of the selected note within the day
+	offset      int // First note of the day scrolled into view
+	width       int
+	height      int
+	quitting    bool
+	prompt      // Open input, if any
+	statusMsg   string
+	focusNoteID int // Note to select after the next reload
+
+	keys calendarKeyMap
+}
+
+// calendarKeyMap holds the calendar's key bindings
+type calendarKeyMap struct {
+	Left      key.Binding
+	Right     key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	PrevNote  key.Binding
+	NextNote  key.Binding
+	PrevMonth key.Binding
+	NextMonth key.Binding
+	Today     key.Binding
+
+	MoveEarlier  key.Binding
+	MoveLater    key.Binding
+	MoveWeekBack key.Binding
+	MoveWeekOn   key.Binding
+	Advance      key.Binding
+	Add          key.Binding
+	ClearDate    key.Binding
+	Quit         key.Binding
+}
+
+// defaultCalendarKeyMap returns the calendar's key bindings
+func defaultCalendarKeyMap() calendarKeyMap {
+	return calendarKeyMap{
+		Left:      binding("Previous day", "left", "h"),
+		Right:     binding("Next day", "right", "l"),
+		Up:        binding("Previous week", "up", "k"),
+		Down:      binding("Next week", "down", "j"),
+		PrevNote:  binding("Previous note", "shift+tab", "["),
+		NextNote:  binding("Next note", "tab", "]"),
+		PrevMonth: binding("Previous month", "pgup", "<"),
+		NextMonth: binding("Next month", "pgdown", ">"),
+		Today:     binding("Today", "t"),
+
+		MoveEarlier:  binding("Move a day earlier", "H", "shift+left"),
+		MoveLater:    binding("Move a day later", "L", "shift+right"),
+		MoveWeekBack: binding("Move a week earlier", "K", "shift+up"),
+		MoveWeekOn:   binding("Move a week later", "J", "shift+down"),
+		Advance:      binding("Advance status", "enter", " "),
+		Add:          binding("Add note on day", "a"),
+		ClearDate:    binding("Clear date", "x"),
+		Quit:         binding("Quit", "q", "ctrl+c"),
+	}
+}
+
+// all returns every binding in the order the instructions list them
+func (k calendarKeyMap) all() []key.Binding {
+	return []key.Binding{
+		k.Left, k.Right, k.Up, k.Down, k.NextNote, k.NextMonth, k.Today,
+		k.MoveEarlier, k.MoveLater, k.MoveWeekOn, k.Advance, k.Add, k.ClearDate, k.Quit,
+	}
+}
+
+// StartCalendar initializes and starts the calendar on the current month
+func StartCalendar(storage *storage.Storage) error {
+	today := startOfDay(time.Now())
+	model := &CalendarModel{
+		storage: storage,
+		cursor:  today,
+		month:   firstOfMonth(today),
+		keys:    defaultCalendarKeyMap(),
+	}
+
+	if err := model.loadNotes(); err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// firstOfMonth returns local midnight on the first day of day's month
+func firstOfMonth(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+// gridStart returns the Monday on or before the first of the month, where
+// the grid begins
+func (m *CalendarModel) gridStart() time.Time {
+	weekday := (int(m.month.Weekday()) + 6) % 7 // Days since Monday
+	return addDays(m.month, -weekday)
+}
+
+// gridWeeks returns how many weeks the grid needs to cover the month
+func (m *CalendarModel) gridWeeks() int {
+	start := m.gridStart()
+	end := addDays(m.month, daysIn(m.month))
+	weeks := 0
+	for day := start; day.Before(end); day = addDays(day, 7) {
+		weeks++
+	}
+	return weeks
+}
+
+// addMonths returns the same day n months on, or the last day of that
+// month when it is shorter
+func addMonths(day time.Time, n int) time.Time {
+	month := firstOfMonth(day).AddDate(0, n, 0)
+	d := day.Day()
+	if last := daysIn(month); d > last {
+		d = last
+	}
+	return addDays(month, d-1)
+}
+
+// daysIn returns the number of days in month's month
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.Local).Day()
+}
+
+// Init implements tea.Model
+func (m *CalendarModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *CalendarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case refreshMsg:
+		if msg.focusNoteID != 0 {
+			m.focusNoteID = msg.focusNoteID
+		}
+		if msg.status != "" {
+			m.statusMsg = msg.status
+		}
+		if err := m.loadNotes(); err != nil {
+			m.statusMsg = fmt.Sprintf("Error loading notes: %v", err)
+		}
+		return m, nil
+
+	case error:
+		m.statusMsg = fmt.Sprintf("Error: %v", msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.promptOpen() {
+			var cmd tea.Cmd
+			cmd, m.quitting = m.handlePromptKey(msg, &m.statusMsg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, m.keys.Left):
+			m.moveCursor(addDays(m.cursor, -1))
+
+		case key.Matches(msg, m.keys.Right):
+			m.moveCursor(addDays(m.cursor, 1))
+
+		case key.Matches(msg, m.keys.Up):
+			m.moveCursor(addDays(m.cursor, -7))
+
+		case key.Matches(msg, m.keys.Down):
+			m.moveCursor(addDays(m.cursor, 7))
+
+		case key.Matches(msg, m.keys.PrevMonth):
+			m.moveCursor(addMonths(m.cursor, -1))
+
+		case key.Matches(msg, m.keys.NextMonth):
+			m.moveCursor(addMonths(m.cursor, 1))
+
+		case key.Matches(msg, m.keys.Today):
+			m.moveCursor(startOfDay(time.Now()))
+
+		case key.Matches(msg, m.keys.PrevNote):
+			m.selectNote(-1)
+
+		case key.Matches(msg, m.keys.NextNote):
+			m.selectNote(1)
+
+		case key.Matches(msg, m.keys.MoveEarlier):
+			return m, m.moveSelectedItem(-1)
+
+		case key.Matches(msg, m.keys.MoveLater):
+			return m, m.moveSelectedItem(1)
+
+		case key.Matches(msg, m.keys.MoveWeekBack):
+			return m, m.moveSelectedItem(-7)
+
+		case key.Matches(msg, m.keys.MoveWeekOn):
+			return m, m.moveSelectedItem(7)
+
+		case key.Matches(msg, m.keys.Advance):
+			return m, m.advanceSelectedNote()
+
+		case key.Matches(msg, m.keys.Add):
+			return m, m.startAddNote()
+
+		case key.Matches(msg, m.keys.ClearDate):
+			return m, m.clearSelectedDate()
+		}
+		return m, nil
+	}
+
+	// Keep the text input's cursor blinking
+	return m, m.updatePrompt(msg)
+}
+
+// View implements tea.Model
+func (m *CalendarModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.renderTitle(),
+		m.renderGrid(),
+		m.renderDayPane(),
+		m.renderStatusLine(),
+		"",
+		m.renderInstructions(),
+	)
+}
+
+// loadNotes loads the notes dated within the grid and restores the
+// selection within the selected day
+func (m *CalendarModel) loadNotes() error {
+	start := m.gridStart()
+	notes, err := m.storage.GetNotesByDateRange(start, addDays(start, 7*m.gridWeeks()))
+	if err != nil {
+		return err
+	}
+	m.days = groupByDay(notes)
+
+	if m.focusNoteID != 0 {
+		for i, item := range m.dayItems() {
+			if item.note.ID == m.focusNoteID {
+				m.selected = i
+			}
+		}
+		m.focusNoteID = 0
+	}
+	m.selectNote(0)
+	return nil
+}
+
+// dayItems returns the notes on the selected day
+func (m *CalendarModel) dayItems() []agendaItem {
+	return m.days[m.cursor.Format(dayFormat)]
+}
+
+// selectedItem returns the selected note on the selected day, if any
+func (m *CalendarModel) selectedItem() *agendaItem {
+	items := m.dayItems()
+	if m.selected < 0 || m.selected >= len(items) {
+		return nil
+	}
+	return &items[m.selected]
+}
+
+// selectNote moves the selection within the day by delta, wrapping around
+func (m *CalendarModel) selectNote(delta int) {
+	items := m.dayItems()
+	if len(items) == 0 {
+		m.selected, m.offset = 0, 0
+		return
+	}
+	m.selected = ((m.selected+delta)%len(items) + len(items)) % len(items)
+	m.offset = scrollOffset(m.offset, m.selected, dayPaneRows-1, len(items))
+}
+
+// moveCursor selects day, switching months and reloading when it falls
+// outside the month shown
+func (m *CalendarModel) moveCursor(day time.Time) {
+	m.cursor = startOfDay(day)
+	m.selected, m.offset = 0, 0
+
+	if month := firstOfMonth(m.cursor); !month.Equal(m.month) {
+		m.month = month
+		if err := m.loadNotes(); err != nil {
+			m.statusMsg = fmt.Sprintf("Error loading notes: %v", err)
+		}
+	}
+}
+
+// moveSelectedItem moves the selected note by days, changing the date it
+// is shown by, and keeps it selected on its new day
+func (m *CalendarModel) moveSelectedItem(days int) tea.Cmd {
+	item := m.selectedItem()
+	if item == nil {
+		return nil
+	}
+	note := item.note
+	target := addDays(item.date(), days)
+
+	dueAt, scheduledAt := note.DueAt, note.ScheduledAt
+	if item.scheduled {
+		scheduledAt = &target
+	} else {
+		dueAt = &target
+	}
+
+	m.moveCursor(target)
+	m.focusNoteID = note.ID
+
+	status := fmt.Sprintf("Moved #%d to %s", note.ID, target.Format("Mon Jan 2"))
+	if !item.scheduled {
+		status = fmt.Sprintf("#%d is now due %s", note.ID, target.Format("Mon Jan 2"))
+	}
+	return m.updateDates(note.ID, dueAt, scheduledAt, status)
+}
+
+// clearSelectedDate removes the date that puts the selected note on this
+// day
+func (m *CalendarModel) clearSelectedDate() tea.Cmd {
+	item := m.selectedItem()
+	if item == nil {
+		return nil
+	}
+	note := item.note
+
+	dueAt, scheduledAt := note.DueAt, note.ScheduledAt
+	if item.scheduled {
+		scheduledAt = nil
+	} else {
+		dueAt = nil
+	}
+	return m.updateDates(note.ID, dueAt, scheduledAt, fmt.Sprintf("Cleared the %s date of #%d", item.kind(), note.ID))
+}
+
+// updateDates saves a note's dates and reloads
+func (m *CalendarModel) updateDates(id int, dueAt, scheduledAt *time.Time, status string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.storage.UpdateNoteDates(id, dueAt, scheduledAt); err != nil {
+			return err
+		}
+		return refreshMsg{focusNoteID: id, status: status}
+	}
+}
+
+// advanceSelectedNote moves the selected note to the next status
+func (m *CalendarModel) advanceSelectedNote() tea.Cmd {
+	item := m.selectedItem()
+	if item == nil {
+		return nil
+	}
+	note := item.note
+
+	status := "todo"
+	for i, s := range storage.Statuses {
+		if s == note.Status {
+			status = storage.Statuses[(i+1)%len(storage.Statuses)]
+		}
+	}
+
+	return func() tea.Msg {
+		if err := m.storage.UpdateNoteStatus(note.ID, status); err != nil {
+			return err
+		}
+		return refreshMsg{focusNoteID: note.ID, status: fmt.Sprintf("Moved #%d to %s", note.ID, strings.ToUpper(status))}
+	}
+}
+
+// startAddNote prompts for a note scheduled on the selected day
+func (m *CalendarModel) startAddNote() tea.Cmd {
+	day := m.cursor
+	label := fmt.Sprintf("New note on %s:", day.Format("Mon Jan 2"))
+
+	return m.openInput(label, "", func(content string) tea.Cmd {
+		note, err := storage.ParseNote(content, time.Now())
+		if err != nil {
+			m.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		if note.Title == "" {
+			m.statusMsg = "Note content cannot be empty"
+			return nil
+		}
+		if note.ScheduledAt == nil && note.DueAt == nil {
+			note.ScheduledAt = &day
+		}
+		return func() tea.Msg {
+			if err := m.storage.CreateNote(note); err != nil {
+				return err
+			}
+			return refreshMsg{focusNoteID: note.ID, status: fmt.Sprintf("Added #%d", note.ID)}
+		}
+	})
+}
+
+// cellSize returns the inner width and height of a day cell, fitting
+// seven columns and the month's weeks in the terminal
+func (m *CalendarModel) cellSize() (int, int) {
+	width, height := m.width, m.height
+	if width == 0 {
+		width, height = 120, 40
+	}
+
+	cellWidth := width/7 - 2
+	if cellWidth < 6 {
+		cellWidth = 6
+	}
+
+	// Title, weekday names, day pane, status line, blank line and
+	// instructions, then two border lines per week
+	chrome := lipgloss.Height(m.renderTitle()) + 1 + dayPaneRows + 1 + 1 + lipgloss.Height(m.renderInstructions())
+	weeks := m.gridWeeks()
+	cellHeight := (height-chrome)/weeks - 2
+	if cellHeight < 1 {
+		cellHeight = 1
+	}
+	return cellWidth, cellHeight
+}
+
+// renderTitle renders the month and year
+func (m *CalendarModel) renderTitle() string {
+	count := 0
+	for day, items := range m.days {
+		if strings.HasPrefix(day, m.month.Format("2006-01")) {
+			count += len(items)
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		active.TitleStyle.Render("📅 "+m.month.Format("January 2006")),
+		"  ",
+		active.MutedStyle.Render(fmt.Sprintf("%d dated this month", count)))
+}
+
+// renderGrid renders the weekday names and a cell per day
+func (m *CalendarModel) renderGrid() string {
+	cellWidth, cellHeight := m.cellSize()
+
+	var names []string
+	for _, name := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		names = append(names, active.LabelStyle.Copy().Width(cellWidth+2).Align(lipgloss.Center).Render(name))
+	}
+	rows := []string{lipgloss.JoinHorizontal(lipgloss.Top, names...)}
+
+	day := m.gridStart()
+	for week := 0; week < m.gridWeeks(); week++ {
+		var cells []string
+		for i := 0; i < 7; i++ {
+			cells = append(cells, m.renderCell(day, cellWidth, cellHeight))
+			day = addDays(day, 1)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderCell renders one day: its number, then as many of its notes as
+// fit and a count of the rest
+func (m *CalendarModel) renderCell(day time.Time, width, height int) string {
+	today := startOfDay(time.Now())
+	items := m.days[day.Format(dayFormat)]
+
+	number := fmt.Sprintf("%d", day.Day())
+	switch {
+	case day.Month() != m.month.Month():
+		number = active.MutedStyle.Render(number)
+	case day.Equal(today):
+		number = active.TitleStyle.Copy().MarginBottom(0).Render(number + " today")
+	default:
+		number = active.LabelStyle.Render(number)
+	}
+
+	lines := []string{number}
+	shown := len(items)
+	if shown > height-1 {
+		shown = height - 2
+		if shown < 0 {
+			shown = 0
+		}
+	}
+	for i, item := range items[:shown] {
+		text := truncate(fmt.Sprintf("#%d %s", item.note.ID, item.note.Title), width)
+		selected := day.Equal(m.cursor) && i == m.selected
+		lines = append(lines, m.itemStyle(item, today, selected).Render(text))
+	}
+	if hidden := len(items) - shown; hidden > 0 {
+		more := fmt.Sprintf("+%d more", hidden)
+		if height == 1 {
+			lines[0] += active.MutedStyle.Render(" " + more)
+		} else {
+			lines = append(lines, active.MutedStyle.Render(more))
+		}
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(active.Border).
+		Width(width).
+		Height(height).
+		MaxHeight(height + 2)
+	if day.Equal(m.cursor) {
+		style = style.BorderForeground(active.Primary)
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+// itemStyle returns the style of a note in the grid or day pane: its
+// status colour, red when overdue, highlighted when selected
+func (m *CalendarModel) itemStyle(item agendaItem, today time.Time, selected bool) lipgloss.Style {
+	switch {
+	case selected:
+		return active.HighlightStyle.Copy().Padding(0)
+	case item.overdue(today):
+		return active.ErrorStyle
+	case item.note.Status == "doing":
+		return active.DoingStyle.Copy().Bold(false)
+	case item.note.Status == "done":
+		return active.MutedStyle.Copy().Strikethrough(true)
+	default:
+		return active.ContentStyle
+	}
+}
+
+// renderDayPane lists the selected day's notes with their status and
+// which date puts them on the day
+func (m *CalendarModel) renderDayPane() string {
+	today := startOfDay(time.Now())
+	items := m.dayItems()
+
+	header := active.LabelStyle.Render(dayLabel(m.cursor, today))
+	if len(items) > 0 {
+		header += active.MutedStyle.Render(fmt.Sprintf(" (%d)", len(items)))
+	}
+	lines := []string{header}
+
+	if len(items) == 0 {
+		lines = append(lines, active.MutedStyle.Render("  Nothing due or scheduled • a to add a note"))
+	}
+	end := m.offset + dayPaneRows - 1
+	if end > len(items) {
+		end = len(items)
+	}
+	for i := m.offset; i < end; i++ {
+		item := items[i]
+		prefix := "  "
+		if i == m.selected {
+			prefix = "▸ "
+		}
+		content := truncate(item.note.Title, 60)
+		lines = append(lines, fmt.Sprintf("%s%-5s %s %s%s", prefix, fmt.Sprintf("#%d", item.note.ID), renderStatus(item.note.Status),
+			m.itemStyle(item, today, i == m.selected).Render(content), active.MutedStyle.Render(" · "+item.kind())))
+	}
+	for len(lines) < dayPaneRows {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderStatusLine renders the active prompt or the last status message
+func (m *CalendarModel) renderStatusLine() string {
+	if prompt := m.promptView(); prompt != "" {
+		return prompt
+	}
+	return active.MutedStyle.Render(m.statusMsg)
+}
+
+// renderInstructions renders the key bindings, wrapped to the terminal
+func (m *CalendarModel) renderInstructions() string {
+	width := m.width
+	if width == 0 {
+		width = 120
+	}
+
+	var instructions []string
+	for _, b := range m.keys.all() {
+		instructions = append(instructions, b.Help().Key+": "+b.Help().Desc)
+	}
+	return active.MutedStyle.Render(wordwrap.String(strings.Join(instructions, " • "), width))
+}
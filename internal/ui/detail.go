@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+	"cheesebox/internal/storage"
+)
+
+// urlPattern matches links written in note content
+var urlPattern = regexp.MustCompile(`https?://[^\s<>()]+`)
+
+// buildDetailStyles builds the theme's detail pane style from its colours
+func (t *Theme) buildDetailStyles() {
+	t.DetailStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 1)
+}
+
+// renderNoteDetail renders the full content and metadata of a note in a
+// pane that is width cells wide, including its border
+func renderNoteDetail(note *storage.Note, width int) string {
+	innerWidth := width - active.DetailStyle.GetHorizontalFrameSize()
+	if innerWidth < 20 {
+		innerWidth = 20
+	}
+	style := active.DetailStyle.Copy().Width(innerWidth + active.DetailStyle.GetHorizontalPadding())
+
+	if note == nil {
+		return style.Render(active.MutedStyle.Render("No card selected"))
+	}
+
+	var sections []string
+
+	header := fmt.Sprintf("#%d %s", note.ID, renderStatus(note.Status))
+	if note.Priority != nil {
+		header += " " + renderPriority(note.Priority)
+	}
+	sections = append(sections, active.LabelStyle.Render(header))
+	sections = append(sections, active.MarkdownHeadingStyle.Render(wordwrap.String(note.Title, innerWidth)))
+	if note.Content != "" {
+		sections = append(sections, renderMarkdown(note.Content, innerWidth))
+	}
+
+	var metadata []string
+	if len(note.Tags) > 0 {
+		metadata = append(metadata, "🏷️  "+strings.Join(note.Tags, ", "))
+	}
+	today := startOfDay(time.Now())
+	if note.DueAt != nil {
+		due := "Due: " + relativeDay(*note.DueAt, today)
+		if note.IsOverdue(today) {
+			due = active.ErrorStyle.Render(due + " (overdue)")
+		}
+		metadata = append(metadata, due)
+	}
+	if note.ScheduledAt != nil {
+		metadata = append(metadata, "Scheduled: "+relativeDay(*note.ScheduledAt, today))
+	}
+	if note.Recurrence != "" {
+		metadata = append(metadata, "Repeats: every:"+note.Recurrence)
+	}
+	if note.ParentID != 0 {
+		metadata = append(metadata, fmt.Sprintf("Subtask of #%d", note.ParentID))
+	}
+	if checked, total := storage.ChecklistProgress(note.Content); total > 0 {
+		metadata = append(metadata, fmt.Sprintf("Checklist: %d of %d done", checked, total))
+	}
+	if note.SeriesID != 0 && note.SeriesID != note.ID {
+		metadata = append(metadata, fmt.Sprintf("Series: started by #%d", note.SeriesID))
+	}
+	metadata = append(metadata,
+		"Created: "+formatTimestamp(note.CreatedAt),
+		"Updated: "+formatTimestamp(note.UpdatedAt),
+		fmt.Sprintf("In %s for %s", strings.ToUpper(note.Status), formatDuration(time.Since(note.StatusChangedAt))),
+	)
+	sections = append(sections, active.MutedStyle.Render(strings.Join(metadata, "\n")))
+
+	if links := urlPattern.FindAllString(note.Text(), -1); len(links) > 0 {
+		var linkLines []string
+		for _, link := range links {
+			linkLines = append(linkLines, "🔗 "+truncate(strings.TrimRight(link, ".,;:!?"), innerWidth-3))
+		}
+		sections = append(sections, active.ContentStyle.Render(strings.Join(linkLines, "\n")))
+	}
+
+	return style.Render(strings.Join(sections, "\n\n"))
+}
+
+// formatTimestamp formats an absolute time followed by its relative age
+func formatTimestamp(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", t.Format("Jan 2, 2006 15:04"), formatTime(t))
+}
+
+// formatDuration formats a duration using its largest whole unit
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
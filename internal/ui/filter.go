@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"cheesebox/internal/search"
+	"cheesebox/internal/storage"
+)
+
+// semanticDelay is how long typing must pause before the filter is sent
+// to Ollama for semantic matches
+const semanticDelay = 400 * time.Millisecond
+
+// Only the closest notes match the filter text by meaning: at most
+// semanticLimit of them, each at least semanticCutoff similar
+const (
+	semanticLimit  = 10
+	semanticCutoff = 0.5
+)
+
+// noteFilter is a parsed board filter. Every term must match: tag terms
+// (tag:name or #name) match tag prefixes, priority terms (priority:p1 or
+// priority:none) match any of the priorities given, and text terms match
+// the content, or its meaning. Text terms written +word must appear
+// literally. A sort:priority or sort:created term orders the cards
+// rather than narrowing them.
+type noteFilter struct {
+	tags       []string
+	priorities map[int]bool // Matching priorities, noPriority for none
+	words      []string
+	required   []string // Words that must appear, even in semantic matches
+	text       string   // Text terms joined, used for the semantic query
+	sort       string   // Card order, or "" to keep the board's
+}
+
+// noPriority stands for notes without a priority in a priority term
+const noPriority = -1
+
+// filterTickMsg fires once typing pauses; seq identifies the keystroke
+type filterTickMsg struct {
+	seq int
+}
+
+// semanticMatchMsg carries the notes that match a filter by meaning
+type semanticMatchMsg struct {
+	text string
+	ids  map[int]bool
+}
+
+// parseFilter splits a filter query into tag, priority, sort and text terms
+func parseFilter(query string) noteFilter {
+	var f noteFilter
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		switch {
+		case strings.HasPrefix(term, "tag:"):
+			if tag := strings.TrimPrefix(term, "tag:"); tag != "" {
+				f.tags = append(f.tags, tag)
+			}
+		case strings.HasPrefix(term, "#"):
+			if tag := strings.TrimPrefix(term, "#"); tag != "" {
+				f.tags = append(f.tags, tag)
+			}
+		case strings.HasPrefix(term, "priority:"):
+			if f.priorities == nil {
+				f.priorities = make(map[int]bool)
+			}
+			value := strings.TrimPrefix(term, "priority:")
+			if value == "none" {
+				f.priorities[noPriority] = true
+			} else if priority, err := storage.ParsePriorityValue(value); err == nil {
+				f.priorities[priority] = true
+			}
+		case term == "sort:"+storage.OrderCreated, term == "sort:"+storage.OrderPriority:
+			f.sort = strings.TrimPrefix(term, "sort:")
+		case strings.HasPrefix(term, "+"):
+			if word := strings.TrimPrefix(term, "+"); word != "" {
+				f.required = append(f.required, word)
+				f.words = append(f.words, word)
+			}
+		default:
+			f.words = append(f.words, term)
+		}
+	}
+	f.text = strings.Join(f.words, " ")
+	return f
+}
+
+// empty reports whether the filter matches everything
+func (f noteFilter) empty() bool {
+	return len(f.tags) == 0 && f.priorities == nil && len(f.words) == 0
+}
+
+// matches reports whether note passes the filter. Notes in semantic match
+// the text terms by meaning even when the words don't appear literally,
+// except for required words.
+func (f noteFilter) matches(note *storage.Note, semantic map[int]bool) bool {
+	for _, want := range f.tags {
+		found := false
+		for _, tag := range note.Tags {
+			if strings.HasPrefix(tag, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.priorities != nil {
+		priority := noPriority
+		if note.Priority != nil {
+			priority = *note.Priority
+		}
+		if !f.priorities[priority] {
+			return false
+		}
+	}
+
+	content := strings.ToLower(note.Title + "\n" + note.Content)
+	for _, word := range f.required {
+		if !strings.Contains(content, word) {
+			return false
+		}
+	}
+	if semantic[note.ID] {
+		return true
+	}
+
+	for _, word := range f.words {
+		if !strings.Contains(content, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyFilter narrows every column to the notes matching the current
+// filter, in the order it asks for
+func (m *KanbanModel) applyFilter() {
+	f := parseFilter(m.filter)
+	semantic := m.semanticIDs
+	if m.semanticText != f.text {
+		semantic = nil
+	}
+
+	for column := 0; column < numColumns; column++ {
+		notes := m.allNotesForColumn(column)
+		if !f.empty() {
+			var matched []*storage.Note
+			for _, note := range notes {
+				if f.matches(note, semantic) {
+					matched = append(matched, note)
+				}
+			}
+			notes = matched
+		}
+		m.visible[column] = sortCards(notes, f.sort)
+	}
+
+	m.groupSwimlanes()
+}
+
+// sortCards returns a column's cards in the given order, or unchanged
+// when order is empty
+func sortCards(notes []*storage.Note, order string) []*storage.Note {
+	if order == "" {
+		return notes
+	}
+
+	sorted := append([]*storage.Note(nil), notes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if order == storage.OrderPriority && a.PriorityRank() != b.PriorityRank() {
+			return a.PriorityRank() < b.PriorityRank()
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return sorted
+}
+
+// setFilter applies a new filter query, keeping the selected card selected
+// when it still matches, and schedules a semantic lookup for the text
+func (m *KanbanModel) setFilter(query string) tea.Cmd {
+	if selected := m.selectedNoteOrNil(); selected != nil {
+		m.focusNoteID = selected.ID
+	}
+	m.filter = query
+	m.applyFilter()
+	m.restoreSelection()
+
+	m.filterSeq++
+	if parseFilter(query).text == "" {
+		return nil
+	}
+	seq := m.filterSeq
+	return tea.Tick(semanticDelay, func(time.Time) tea.Msg {
+		return filterTickMsg{seq: seq}
+	})
+}
+
+// semanticMatches looks up the notes closest to text in meaning. It yields
+// no message when Ollama is unavailable or no notes have embeddings yet.
+func (m *KanbanModel) semanticMatches(text string) tea.Cmd {
+	return func() tea.Msg {
+		client := search.NewOllamaClient("")
+		if !client.IsAvailable() {
+			return nil
+		}
+
+		results, err := client.SearchSemantic(m.storage, text, semanticLimit)
+		if err != nil || len(results) == 0 {
+			return nil
+		}
+
+		ids := make(map[int]bool, len(results))
+		for _, result := range results {
+			if result.Similarity >= semanticCutoff {
+				ids[result.Note.ID] = true
+			}
+		}
+		return semanticMatchMsg{text: text, ids: ids}
+	}
+}
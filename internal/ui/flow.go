@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cheesebox/internal/stats"
+)
+
+// Cumulative flow diagram size: rows, and the most days drawn before days
+// are sampled to fit
+const (
+	cfdHeight     = 10
+	cfdMaxColumns = 90
+)
+
+// cfdBands lists the statuses stacked in the cumulative flow diagram,
+// bottom first, with the glyph that draws each so the bands stay apart
+// without colour
+var cfdBands = []struct {
+	status string
+	glyph  string
+}{
+	{"done", "█"},
+	{"doing", "▓"},
+	{"todo", "░"},
+}
+
+// RenderFlow renders flow metrics: lead and cycle time, weekly
+// throughput, aging work in progress and a cumulative flow diagram
+func RenderFlow(flow *stats.Flow) string {
+	var output strings.Builder
+	output.WriteString(active.TitleStyle.Render("📈 Flow since " + flow.From.Format("Mon Jan 2")))
+	output.WriteString("\n")
+
+	output.WriteString(renderSummary("Lead time ", "created → done", flow.LeadTime))
+	output.WriteString(renderSummary("Cycle time", "doing → done", flow.CycleTime))
+	output.WriteString("\n")
+
+	output.WriteString(active.LabelStyle.Render("Throughput per week"))
+	output.WriteString("\n")
+	longest := 0
+	for _, week := range flow.Throughput {
+		if week.Count > longest {
+			longest = week.Count
+		}
+	}
+	for _, week := range flow.Throughput {
+		bar := ""
+		if longest > 0 {
+			bar = strings.Repeat("█", (reportBarWidth*week.Count+longest-1)/longest)
+		}
+		output.WriteString(fmt.Sprintf("  %s  %s %s\n", active.ContentStyle.Render(fmt.Sprintf("%-10s", week.Start.Format("Mon Jan 2"))),
+			active.BarStyle.Render(bar), active.MutedStyle.Render(fmt.Sprintf("%d", week.Count))))
+	}
+	output.WriteString("\n")
+
+	output.WriteString(active.LabelStyle.Render(fmt.Sprintf("Aging work in progress (%d)", len(flow.Aging))))
+	output.WriteString("\n")
+	if len(flow.Aging) == 0 {
+		output.WriteString(active.MutedStyle.Render("  Nothing in DOING"))
+		output.WriteString("\n")
+	}
+	for _, item := range flow.Aging {
+		// Work older than 85% of completed cycle times is at risk
+		style := active.ContentStyle
+		if flow.CycleTime.Count > 0 && item.Age > flow.CycleTime.P85 {
+			style = active.ErrorStyle
+		}
+		content := truncate(item.Note.Title, 50)
+		output.WriteString(fmt.Sprintf("  %-5s %s %s\n", fmt.Sprintf("#%d", item.Note.ID),
+			style.Render(fmt.Sprintf("%-8s", formatSpan(item.Age))), active.ContentStyle.Render(content)))
+	}
+	output.WriteString("\n")
+
+	output.WriteString(active.LabelStyle.Render("Cumulative flow"))
+	output.WriteString("\n")
+	output.WriteString(renderCFD(flow.CFD))
+	return output.String()
+}
+
+// renderSummary renders one line describing a set of durations
+func renderSummary(label, span string, summary stats.Summary) string {
+	line := fmt.Sprintf("%s  %s", active.LabelStyle.Render(label), active.MutedStyle.Render(fmt.Sprintf("(%s)", span)))
+	if summary.Count == 0 {
+		return line + "  " + active.MutedStyle.Render("no notes completed") + "\n"
+	}
+	notes := "notes"
+	if summary.Count == 1 {
+		notes = "note"
+	}
+	return line + "  " + active.ContentStyle.Render(fmt.Sprintf("%d %s · median %s · mean %s · 85%% within %s",
+		summary.Count, notes, formatSpan(summary.Median), formatSpan(summary.Mean), formatSpan(summary.P85))) + "\n"
+}
+
+// renderCFD draws a cumulative flow diagram: one column per day with the
+// notes in each status stacked, done at the bottom
+func renderCFD(days []stats.DayCounts) string {
+	step := (len(days) + cfdMaxColumns - 1) / cfdMaxColumns
+	if step < 1 {
+		step = 1
+	}
+	var sampled []stats.DayCounts
+	for i := len(days) - 1; i >= 0; i -= step {
+		sampled = append([]stats.DayCounts{days[i]}, sampled...)
+	}
+
+	most := 0
+	for _, day := range sampled {
+		total := 0
+		for _, count := range day.Counts {
+			total += count
+		}
+		if total > most {
+			most = total
+		}
+	}
+	if most == 0 {
+		return active.MutedStyle.Render("  No notes in this period") + "\n"
+	}
+
+	// Each band's top edge in rows, per column
+	tops := make([][]int, len(sampled))
+	for i, day := range sampled {
+		cumulative := 0
+		for _, band := range cfdBands {
+			cumulative += day.Counts[band.status]
+			tops[i] = append(tops[i], (cumulative*cfdHeight+most/2)/most)
+		}
+	}
+
+	axisWidth := len(fmt.Sprintf("%d", most))
+	var output strings.Builder
+	for row := cfdHeight - 1; row >= 0; row-- {
+		axis := strings.Repeat(" ", axisWidth)
+		switch row {
+		case cfdHeight - 1:
+			axis = fmt.Sprintf("%*d", axisWidth, most)
+		case 0:
+			axis = fmt.Sprintf("%*d", axisWidth, 0)
+		}
+		output.WriteString(active.MutedStyle.Render(axis + " ┤"))
+
+		for i := range sampled {
+			cell := " "
+			for b, band := range cfdBands {
+				if row < tops[i][b] {
+					cell = statusStyle(band.status).Render(band.glyph)
+					break
+				}
+			}
+			output.WriteString(cell)
+		}
+		output.WriteString("\n")
+	}
+
+	// Dates under the first and last columns
+	first := sampled[0].Day.Format("Jan 2")
+	last := sampled[len(sampled)-1].Day.Format("Jan 2")
+	gap := len(sampled) - len(first) - len(last)
+	if gap < 1 {
+		gap = 1
+	}
+	output.WriteString(active.MutedStyle.Render(strings.Repeat(" ", axisWidth+2) + first + strings.Repeat(" ", gap) + last))
+	output.WriteString("\n")
+
+	var legend []string
+	for _, band := range cfdBands {
+		legend = append(legend, statusStyle(band.status).Render(band.glyph)+" "+strings.ToUpper(band.status))
+	}
+	output.WriteString(strings.Repeat(" ", axisWidth+2) + strings.Join(legend, "  "))
+	return output.String()
+}
+
+// formatSpan formats a duration in its two largest units, like 3d 4h
+func formatSpan(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours()/24), int(d.Hours())%24)
+	}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore reports whether the runes of pattern appear in text in order,
+// ignoring case, and scores the match. Runes that follow the previous match
+// directly or start a word score higher, so tighter matches rank first.
+func fuzzyScore(pattern, text string) (int, bool) {
+	pattern = strings.ToLower(pattern)
+	if pattern == "" {
+		return 0, true
+	}
+	want := []rune(pattern)
+
+	score := 0
+	next := 0
+	last := -2
+	prev := ' '
+	for i, r := range []rune(strings.ToLower(text)) {
+		if next < len(want) && r == want[next] {
+			score++
+			if i == last+1 {
+				score += 3
+			}
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 2
+			}
+			last = i
+			next++
+		}
+		prev = r
+	}
+
+	if next < len(want) {
+		return 0, false
+	}
+	return score, true
+}
+
+// fuzzyMatch matches every space-separated term of query against text and
+// returns the summed score
+func fuzzyMatch(query, text string) (int, bool) {
+	total := 0
+	for _, term := range strings.Fields(query) {
+		score, ok := fuzzyScore(term, text)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
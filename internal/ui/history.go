@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+)
+
+// maxHistory is how many operations the undo stack keeps
+const maxHistory = 100
+
+// boardOp is an undoable board operation. redo performs the operation and
+// undo runs the inverse storage operation.
+type boardOp struct {
+	desc   string // e.g. "move #12 to DOING"
+	noteID int    // Card to select after the operation runs either way
+	undo   func() error
+	redo   func() error
+}
+
+// undoneMsg reports that op was undone
+type undoneMsg struct {
+	op *boardOp
+}
+
+// redoneMsg reports that op was redone
+type redoneMsg struct {
+	op *boardOp
+}
+
+// runOp performs op and records it on the undo stack once it succeeds.
+// status is the feedback shown afterwards, with any %d replaced by the
+// op's note ID since an added note's ID is only known once it has run.
+func (m *KanbanModel) runOp(op *boardOp, status string) tea.Cmd {
+	return func() tea.Msg {
+		if err := op.redo(); err != nil {
+			return err
+		}
+		status = strings.ReplaceAll(status, "%d", strconv.Itoa(op.noteID))
+		return refreshMsg{focusNoteID: op.noteID, status: status, op: op}
+	}
+}
+
+// recordOp pushes a newly performed operation onto the undo stack and
+// clears the redo stack
+func (m *KanbanModel) recordOp(op *boardOp) {
+	m.undoStack = append(m.undoStack, op)
+	if len(m.undoStack) > maxHistory {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxHistory:]
+	}
+	m.redoStack = nil
+}
+
+// undo reverts the most recent operation
+func (m *KanbanModel) undo() tea.Cmd {
+	if len(m.undoStack) == 0 {
+		m.statusMsg = "Nothing to undo"
+		return nil
+	}
+
+	op := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+
+	return func() tea.Msg {
+		if err := op.undo(); err != nil {
+			return fmt.Errorf("failed to undo %s: %w", op.desc, err)
+		}
+		return undoneMsg{op: op}
+	}
+}
+
+// redo performs the most recently undone operation again
+func (m *KanbanModel) redo() tea.Cmd {
+	if len(m.redoStack) == 0 {
+		m.statusMsg = "Nothing to redo"
+		return nil
+	}
+
+	op := m.redoStack[len(m.redoStack)-1]
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+
+	return func() tea.Msg {
+		if err := op.redo(); err != nil {
+			return fmt.Errorf("failed to redo %s: %w", op.desc, err)
+		}
+		return redoneMsg{op: op}
+	}
+}
@@ -2,7 +2,11 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"cheesebox/internal/storage"
@@ -19,29 +23,136 @@ type KanbanModel struct {
 	width          int
 	height         int
 	quitting       bool
+	prompt                         // Open input or confirmation, if any
+	statusMsg      string          // Feedback shown above the instructions
+	focusNoteID    int             // Note to select after the next reload
+	showDetail     bool            // Whether the card detail pane is visible
+	showHelp       bool            // Whether the keybinding help overlay is shown
+	scroll         [numColumns]int // First visible card in each column
+
+	// Board filter: the query, the notes it leaves in each column and the
+	// notes Ollama matched by meaning for semanticText
+	filter       string
+	visible      [numColumns][]*storage.Note
+	filterSeq    int
+	semanticText string
+	semanticIDs  map[int]bool
+
+	// Swimlanes: the grouping, its tag prefix, the lanes built from the
+	// filtered notes, which lanes are collapsed, the lane last collapsed
+	// from the keyboard and the shared scroll offset
+	laneMode       string
+	lanePrefix     string
+	swimlanes      []*swimlane
+	collapsedLanes map[string]bool
+	lastCollapsed  *collapsedLane
+	laneScroll     int
+
+	wipLimits map[string]int
+	wipBlock  bool
+
+	// Card order within each column, as accepted by GetNotesByStatus
+	order string
+
+	// IDs of the cards marked for bulk actions
+	marked map[int]bool
+
+	// Running timer, if any
+	timer *storage.TimeEntry
+
+	// Rolled-up subtask progress of each note with subtasks
+	progress map[int]storage.Progress
+
+	// Card being dragged with the mouse, if any
+	drag *dragState
+
+	// Database data version last seen, to reload on outside changes
+	dataVersion int64
+
+	keys keyMap
+
+	// Undo history of board operations, most recent last
+	undoStack []*boardOp
+	redoStack []*boardOp
 }
 
+// KanbanOptions configures the kanban board
+type KanbanOptions struct {
+	// Swimlanes is the initial swimlane grouping, as accepted by
+	// ParseSwimlanes. Empty shows the board without lanes.
+	Swimlanes string
+
+	// Order is the card order within each column: storage.OrderCreated
+	// (the default) or storage.OrderPriority
+	Order string
+
+	// WIPLimits maps a status to the most cards its column should hold;
+	// 0 or missing means unlimited. Moves past a limit ask for
+	// confirmation, or are refused when WIPBlock is set.
+	WIPLimits map[string]int
+	WIPBlock  bool
+
+	// Keys overrides key bindings, mapping an action name such as "up" or
+	// "move_next" to the keys that trigger it. An empty list unbinds it.
+	Keys map[string][]string
+}
+
+const (
+	numColumns     = 3
+	terminalColumn = 2 // Cards leaving this column need confirmation
+
+	// minBoardWidth is the narrowest the three columns can be rendered,
+	// used to decide whether the detail pane fits beside them
+	minBoardWidth = 3*(25+2) + 10
+
+	// Cards shown per column before the terminal size is known, and the
+	// fewest shown on a short terminal
+	defaultColumnRows = 18
+	minColumnRows     = 3
+)
+
 // StartKanban initializes and starts the kanban board interface
-func StartKanban(storage *storage.Storage) error {
+func StartKanban(storage *storage.Storage, opts KanbanOptions) error {
+	laneMode, lanePrefix, err := ParseSwimlanes(opts.Swimlanes)
+	if err != nil {
+		return err
+	}
+
+	keys, err := newKeyMap(opts.Keys)
+	if err != nil {
+		return fmt.Errorf("invalid key bindings: %w", err)
+	}
+
 	model := &KanbanModel{
 		storage:        storage,
 		selectedColumn: 0,
 		selectedNote:   0,
+		laneMode:       laneMode,
+		lanePrefix:     lanePrefix,
+		collapsedLanes: make(map[string]bool),
+		marked:         make(map[int]bool),
+		wipLimits:      opts.WIPLimits,
+		wipBlock:       opts.WIPBlock,
+		order:          opts.Order,
+		keys:           keys,
 	}
 
-	// Load initial data
+	// Load initial data, noting the data version it reflects
+	if version, err := storage.DataVersion(); err == nil {
+		model.dataVersion = version
+	}
 	if err := model.loadNotes(); err != nil {
 		return fmt.Errorf("failed to load notes: %w", err)
 	}
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	_, err := p.Run()
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	_, err = p.Run()
 	return err
 }
 
 // Init implements tea.Model
 func (m *KanbanModel) Init() tea.Cmd {
-	return nil
+	return m.watchChanges()
 }
 
 // Update implements tea.Model
@@ -50,52 +161,215 @@ func (m *KanbanModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.scrollToSelection()
+		return m, nil
+
+	case refreshMsg:
+		if msg.op != nil {
+			m.recordOp(msg.op)
+		}
+		if msg.focusNoteID != 0 {
+			m.focusNoteID = msg.focusNoteID
+		}
+		if msg.status != "" {
+			m.statusMsg = msg.status
+		}
+		if err := m.loadNotes(); err != nil {
+			m.statusMsg = fmt.Sprintf("Error loading notes: %v", err)
+		}
+		return m, nil
+
+	case error:
+		m.statusMsg = fmt.Sprintf("Error: %v", msg)
+		return m, nil
+
+	case dataVersionMsg:
+		return m, m.handleDataVersion(msg)
+
+	case undoneMsg:
+		m.redoStack = append(m.redoStack, msg.op)
+		m.statusMsg = "Undid " + msg.op.desc
+		m.focusNoteID = msg.op.noteID
+		return m, m.refresh()
+
+	case redoneMsg:
+		m.undoStack = append(m.undoStack, msg.op)
+		m.statusMsg = "Redid " + msg.op.desc
+		m.focusNoteID = msg.op.noteID
+		return m, m.refresh()
+
+	case filterTickMsg:
+		if text := parseFilter(m.filter).text; msg.seq == m.filterSeq && text != m.semanticText {
+			return m, m.semanticMatches(text)
+		}
+		return m, nil
+
+	case semanticMatchMsg:
+		if msg.text == parseFilter(m.filter).text {
+			if selected := m.selectedNoteOrNil(); selected != nil {
+				m.focusNoteID = selected.ID
+			}
+			m.semanticText = msg.text
+			m.semanticIDs = msg.ids
+			m.applyFilter()
+			m.restoreSelection()
+		}
 		return m, nil
 
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		if m.promptOpen() {
+			var cmd tea.Cmd
+			cmd, m.quitting = m.handlePromptKey(msg, &m.statusMsg)
+			return m, cmd
+		}
+
+		if m.showHelp {
+			if key.Matches(msg, m.keys.Help, m.keys.Clear) {
+				m.showHelp = false
+			}
+			if msg.String() == "ctrl+c" || key.Matches(msg, m.keys.Quit) {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		switch {
+		case msg.String() == "ctrl+c", key.Matches(msg, m.keys.Quit):
 			m.quitting = true
 			return m, tea.Quit
 
-		case "left", "h":
+		case key.Matches(msg, m.keys.Left):
 			if m.selectedColumn > 0 {
 				m.selectedColumn--
-				m.selectedNote = 0 // Reset note selection when changing columns
+				m.selectedNote = m.firstVisibleNote(m.selectedColumn)
 			}
 			return m, nil
 
-		case "right", "l":
-			if m.selectedColumn < 2 {
+		case key.Matches(msg, m.keys.Right):
+			if m.selectedColumn < numColumns-1 {
 				m.selectedColumn++
-				m.selectedNote = 0 // Reset note selection when changing columns
+				m.selectedNote = m.firstVisibleNote(m.selectedColumn)
 			}
 			return m, nil
 
-		case "up", "k":
-			notes := m.getNotesForColumn(m.selectedColumn)
-			if len(notes) > 0 && m.selectedNote > 0 {
-				m.selectedNote--
-			}
+		case key.Matches(msg, m.keys.Up):
+			m.selectNote(-1)
 			return m, nil
 
-		case "down", "j":
-			notes := m.getNotesForColumn(m.selectedColumn)
-			if len(notes) > 0 && m.selectedNote < len(notes)-1 {
-				m.selectedNote++
-			}
+		case key.Matches(msg, m.keys.Down):
+			m.selectNote(1)
+			return m, nil
+
+		case key.Matches(msg, m.keys.PageUp):
+			m.selectNote(-m.layout().rows)
+			return m, nil
+
+		case key.Matches(msg, m.keys.PageDown):
+			m.selectNote(m.layout().rows)
+			return m, nil
+
+		case key.Matches(msg, m.keys.Top):
+			m.selectNote(-m.selectedNote)
 			return m, nil
 
-		case "enter", " ":
+		case key.Matches(msg, m.keys.Bottom):
+			m.selectNote(len(m.getNotesForColumn(m.selectedColumn)))
+			return m, nil
+
+		case key.Matches(msg, m.keys.MoveNext):
 			return m, m.moveSelectedNote()
 
-		case "r":
+		case key.Matches(msg, m.keys.MoveLeft):
+			return m, m.moveSelectedNoteTo(m.selectedColumn - 1)
+
+		case key.Matches(msg, m.keys.MoveRight):
+			return m, m.moveSelectedNoteTo(m.selectedColumn + 1)
+
+		case key.Matches(msg, m.keys.MoveTodo):
+			return m, m.moveSelectedNoteTo(0)
+
+		case key.Matches(msg, m.keys.MoveDoing):
+			return m, m.moveSelectedNoteTo(1)
+
+		case key.Matches(msg, m.keys.MoveDone):
+			return m, m.moveSelectedNoteTo(2)
+
+		case key.Matches(msg, m.keys.Filter):
+			return m, m.startFilter()
+
+		case key.Matches(msg, m.keys.Clear):
+			if len(m.marked) > 0 {
+				m.clearMarks()
+				m.statusMsg = "Selection cleared"
+			} else if m.filter != "" {
+				m.setFilter("")
+				m.statusMsg = "Filter cleared"
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Swimlanes):
+			return m, m.cycleSwimlanes()
+
+		case key.Matches(msg, m.keys.CollapseLane):
+			m.toggleSelectedLane()
+			return m, nil
+
+		case key.Matches(msg, m.keys.ExpandLanes):
+			m.expandAllLanes()
+			return m, nil
+
+		case key.Matches(msg, m.keys.Details):
+			m.showDetail = !m.showDetail
+			return m, nil
+
+		case key.Matches(msg, m.keys.Add):
+			return m, m.startAddNote()
+
+		case key.Matches(msg, m.keys.Edit):
+			return m, m.startEditNote()
+
+		case key.Matches(msg, m.keys.Tags):
+			return m, m.startEditTags()
+
+		case key.Matches(msg, m.keys.Delete):
+			m.confirmDeleteNote()
+			return m, nil
+
+		case key.Matches(msg, m.keys.Mark):
+			m.toggleMark()
+			return m, nil
+
+		case key.Matches(msg, m.keys.Archive):
+			return m, m.archiveNotes()
+
+		case key.Matches(msg, m.keys.Timer):
+			return m, m.toggleTimer()
+
+		case key.Matches(msg, m.keys.Check):
+			return m, m.startCheckItem()
+
+		case key.Matches(msg, m.keys.Undo):
+			return m, m.undo()
+
+		case key.Matches(msg, m.keys.Redo):
+			return m, m.redo()
+
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, m.keys.Refresh):
 			// Refresh data
 			return m, m.refresh()
 		}
 	}
 
-	return m, nil
+	// Keep the text input's cursor blinking
+	return m, m.updatePrompt(msg)
 }
 
 // View implements tea.Model
@@ -103,6 +377,9 @@ func (m *KanbanModel) View() string {
 	if m.quitting {
 		return "Thanks for using Cheesebox! 🧀\n"
 	}
+	if m.showHelp {
+		return m.renderHelp()
+	}
 
 	return m.renderKanbanBoard()
 }
@@ -111,26 +388,66 @@ func (m *KanbanModel) View() string {
 func (m *KanbanModel) loadNotes() error {
 	var err error
 
-	m.todoNotes, err = m.storage.GetNotesByStatus("todo")
+	m.todoNotes, err = m.storage.GetNotesByStatus("todo", m.order)
+	if err != nil {
+		return err
+	}
+
+	m.doingNotes, err = m.storage.GetNotesByStatus("doing", m.order)
 	if err != nil {
 		return err
 	}
 
-	m.doingNotes, err = m.storage.GetNotesByStatus("doing")
+	m.doneNotes, err = m.storage.GetNotesByStatus("done", m.order)
 	if err != nil {
 		return err
 	}
 
-	m.doneNotes, err = m.storage.GetNotesByStatus("done")
+	m.timer, err = m.storage.ActiveTimer()
 	if err != nil {
 		return err
 	}
 
+	m.progress, err = m.storage.GetProgress()
+	if err != nil {
+		return err
+	}
+
+	m.pruneMarks()
+	m.applyFilter()
+	m.restoreSelection()
 	return nil
 }
 
-// getNotesForColumn returns the notes for a specific column
+// restoreSelection keeps the selection valid after the columns change,
+// following focusNoteID to its new column when one is set
+func (m *KanbanModel) restoreSelection() {
+	if m.focusNoteID != 0 {
+		for column := 0; column < numColumns; column++ {
+			for i, note := range m.getNotesForColumn(column) {
+				if note.ID == m.focusNoteID {
+					m.selectedColumn = column
+					m.selectedNote = i
+				}
+			}
+		}
+		m.focusNoteID = 0
+	}
+
+	m.selectNote(0)
+}
+
+// getNotesForColumn returns the notes shown in a specific column, which
+// is every note with its status unless a filter is active
 func (m *KanbanModel) getNotesForColumn(column int) []*storage.Note {
+	if column < 0 || column >= numColumns {
+		return nil
+	}
+	return m.visible[column]
+}
+
+// allNotesForColumn returns every note with the column's status
+func (m *KanbanModel) allNotesForColumn(column int) []*storage.Note {
 	switch column {
 	case 0:
 		return m.todoNotes
@@ -157,55 +474,427 @@ func (m *KanbanModel) getStatusForColumn(column int) string {
 	}
 }
 
-// moveSelectedNote moves the selected note to the next column
+// moveSelectedNote moves the selected note to the next column,
+// cycling done back to todo
 func (m *KanbanModel) moveSelectedNote() tea.Cmd {
-	notes := m.getNotesForColumn(m.selectedColumn)
-	if len(notes) == 0 || m.selectedNote >= len(notes) {
+	return m.moveSelectedNoteTo((m.selectedColumn + 1) % numColumns)
+}
+
+// moveSelectedNoteTo moves the selected note, or every marked note, to the
+// given column and keeps it selected. Moving a note out of the terminal
+// column asks for confirmation.
+func (m *KanbanModel) moveSelectedNoteTo(column int) tea.Cmd {
+	if len(m.marked) > 0 {
+		return m.moveMarkedNotesTo(column)
+	}
+
+	selectedNote := m.selectedNoteOrNil()
+	if selectedNote == nil {
 		return nil
 	}
+	return m.moveNoteTo(selectedNote, column)
+}
 
-	selectedNote := notes[m.selectedNote]
-	var newStatus string
+// moveNoteTo moves a note to the given column, asking for confirmation
+// when it leaves the terminal column or exceeds a WIP limit
+func (m *KanbanModel) moveNoteTo(selectedNote *storage.Note, column int) tea.Cmd {
+	if column < 0 || column >= numColumns {
+		return nil
+	}
+	newStatus := m.getStatusForColumn(column)
+	if newStatus == selectedNote.Status {
+		return nil
+	}
 
-	// Determine new status based on current column
-	switch m.selectedColumn {
-	case 0: // todo -> doing
-		newStatus = "doing"
-	case 1: // doing -> done
-		newStatus = "done"
-	case 2: // done -> todo (cycle back)
-		newStatus = "todo"
+	oldStatus := selectedNote.Status
+	move := func() tea.Cmd {
+		return m.runOp(&boardOp{
+			desc:   fmt.Sprintf("move #%d to %s", selectedNote.ID, strings.ToUpper(newStatus)),
+			noteID: selectedNote.ID,
+			undo: func() error {
+				return m.storage.UpdateNoteStatus(selectedNote.ID, oldStatus)
+			},
+			redo: func() error {
+				return m.storage.UpdateNoteStatus(selectedNote.ID, newStatus)
+			},
+		}, "Moved #%d to "+strings.ToUpper(newStatus))
 	}
 
-	return tea.Cmd(func() tea.Msg {
-		err := m.storage.UpdateNoteStatus(selectedNote.ID, newStatus)
+	// Moves out of the terminal column and past a WIP limit need confirmation
+	var warnings []string
+	if selectedNote.Status == m.getStatusForColumn(terminalColumn) {
+		warnings = append(warnings, fmt.Sprintf("Move #%d out of %s to %s?",
+			selectedNote.ID, strings.ToUpper(selectedNote.Status), strings.ToUpper(newStatus)))
+	}
+	if m.exceedsWIPLimit(column, 1) {
+		limit := m.wipLimits[newStatus]
+		if m.wipBlock {
+			m.statusMsg = fmt.Sprintf("%s is at its WIP limit (%d)", strings.ToUpper(newStatus), limit)
+			return nil
+		}
+		warnings = append(warnings, fmt.Sprintf("%s would exceed its WIP limit (%d/%d). Move #%d anyway?",
+			strings.ToUpper(newStatus), len(m.allNotesForColumn(column))+1, limit, selectedNote.ID))
+	}
+
+	if len(warnings) > 0 {
+		m.confirm = &confirmation{
+			prompt: strings.Join(warnings, " "),
+			action: move,
+		}
+		return nil
+	}
+
+	return move()
+}
+
+// toggleTimer starts the timer on the selected card, stopping any other,
+// or stops it when it is already timing that card
+func (m *KanbanModel) toggleTimer() tea.Cmd {
+	note := m.selectedNoteOrNil()
+	if note == nil {
+		return nil
+	}
+
+	running := m.timer != nil && m.timer.NoteID == note.ID
+	return func() tea.Msg {
+		if running {
+			stopped, err := m.storage.StopTimer()
+			if err != nil {
+				return err
+			}
+			status := fmt.Sprintf("Stopped #%d", note.ID)
+			if stopped != nil {
+				status += " after " + FormatTracked(stopped.Duration(time.Now()))
+			}
+			return refreshMsg{focusNoteID: note.ID, status: status}
+		}
+
+		if _, err := m.storage.StartTimer(note.ID); err != nil {
+			return err
+		}
+		return refreshMsg{focusNoteID: note.ID, status: fmt.Sprintf("Timing #%d", note.ID)}
+	}
+}
+
+// startCheckItem toggles an item of the selected note's checklist. With
+// several items it opens the detail pane, where the items are numbered,
+// and prompts for the number to toggle.
+func (m *KanbanModel) startCheckItem() tea.Cmd {
+	note := m.selectedNoteOrNil()
+	if note == nil {
+		return nil
+	}
+
+	items := storage.ParseChecklist(note.Content)
+	switch len(items) {
+	case 0:
+		m.statusMsg = fmt.Sprintf("#%d has no checklist", note.ID)
+		return nil
+	case 1:
+		return m.checkItem(note, 1)
+	}
+
+	m.showDetail = true
+	label := fmt.Sprintf("Toggle item on #%d (1-%d):", note.ID, len(items))
+	return m.openInput(label, "", func(value string) tea.Cmd {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > len(items) {
+			m.statusMsg = fmt.Sprintf("No checklist item %q on #%d", value, note.ID)
+			return nil
+		}
+		return m.checkItem(note, n)
+	})
+}
+
+// checkItem toggles the nth checklist item of a note, undone by writing
+// back its original content, and status if checking the item completed it
+func (m *KanbanModel) checkItem(note *storage.Note, n int) tea.Cmd {
+	content, err := storage.ToggleChecklistItem(note.Content, n)
+	if err != nil {
+		m.statusMsg = "Error: " + err.Error()
+		return nil
+	}
+	verb := "Unchecked"
+	if storage.ParseChecklist(content)[n-1].Checked {
+		verb = "Checked"
+	}
+	checked, total := storage.ChecklistProgress(content)
+
+	var toggled *storage.Note
+	return m.runOp(&boardOp{
+		desc:   fmt.Sprintf("toggle item %d on #%d", n, note.ID),
+		noteID: note.ID,
+		undo: func() error {
+			return m.saveChangedFields(note, toggled, note)
+		},
+		redo: func() (err error) {
+			toggled, err = m.storage.CheckItem(note.ID, n)
+			return err
+		},
+	}, fmt.Sprintf("%s item %d on #%%d (%d/%d)", verb, n, checked, total))
+}
+
+// exceedsWIPLimit reports whether adding count cards to a column would
+// take it past its status's WIP limit
+func (m *KanbanModel) exceedsWIPLimit(column, count int) bool {
+	limit := m.wipLimits[m.getStatusForColumn(column)]
+	return limit > 0 && len(m.allNotesForColumn(column))+count > limit
+}
+
+// selectedNoteOrNil returns the currently selected note, if any
+func (m *KanbanModel) selectedNoteOrNil() *storage.Note {
+	notes := m.getNotesForColumn(m.selectedColumn)
+	if m.selectedNote < 0 || m.selectedNote >= len(notes) {
+		return nil
+	}
+	return notes[m.selectedNote]
+}
+
+// startFilter opens the filter prompt, narrowing the board as the user types
+func (m *KanbanModel) startFilter() tea.Cmd {
+	cmd := m.openInput("Filter:", m.filter, func(value string) tea.Cmd {
+		m.statusMsg = ""
+		return nil
+	})
+	m.input.onChange = m.setFilter
+	m.input.cancel = func() {
+		m.statusMsg = "Filter cleared"
+		m.setFilter("")
+	}
+	return cmd
+}
+
+// startAddNote prompts for a new note in the focused column
+func (m *KanbanModel) startAddNote() tea.Cmd {
+	status := m.getStatusForColumn(m.selectedColumn)
+	label := fmt.Sprintf("New %s note:", strings.ToUpper(status))
+
+	return m.openInput(label, "", func(content string) tea.Cmd {
+		note, err := storage.ParseNote(content, time.Now())
 		if err != nil {
+			m.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		if note.Title == "" {
+			m.statusMsg = "Note content cannot be empty"
+			return nil
+		}
+		note.Status = status
+
+		// The first run adds the note; redoing after an undo restores it
+		var added *storage.Note
+		op := &boardOp{}
+		op.redo = func() error {
+			if added != nil {
+				return m.storage.RestoreNote(added)
+			}
+			if err := m.storage.CreateNote(note); err != nil {
+				return err
+			}
+			added = note
+			op.noteID = note.ID
+			op.desc = fmt.Sprintf("add #%d", note.ID)
+			return nil
+		}
+		op.undo = func() error {
+			deleted, err := m.storage.DeleteNotes([]int{added.ID})
+			if err == nil && len(deleted) > 0 {
+				added = deleted[0]
+			}
 			return err
 		}
-		return refreshMsg{}
+		return m.runOp(op, "Added #%d")
+	})
+}
+
+// startEditNote prompts for a new title for the selected note, keeping
+// its body. Dates and a priority written inline replace the note's.
+func (m *KanbanModel) startEditNote() tea.Cmd {
+	note := m.selectedNoteOrNil()
+	if note == nil {
+		return nil
+	}
+	label := fmt.Sprintf("Edit #%d:", note.ID)
+
+	// The prompt edits the title; the body is kept
+	return m.openInput(label, note.Title, func(title string) tea.Cmd {
+		edited, err := note.Edit(storage.JoinNote(title, note.Content), time.Now())
+		if err != nil {
+			m.statusMsg = "Error: " + err.Error()
+			return nil
+		}
+		if edited.Title == "" {
+			m.statusMsg = "Note content cannot be empty"
+			return nil
+		}
+		return m.runOp(m.updateNoteOp(note, edited, "edit"), "Updated #%d")
+	})
+}
+
+// startEditTags prompts for the selected note's tags as space-separated
+// words, or for tags to add to every marked note
+func (m *KanbanModel) startEditTags() tea.Cmd {
+	if len(m.marked) > 0 {
+		return m.startTagMarkedNotes()
+	}
+
+	note := m.selectedNoteOrNil()
+	if note == nil {
+		return nil
+	}
+	label := fmt.Sprintf("Tags for #%d:", note.ID)
+
+	var current []string
+	for _, tag := range note.Tags {
+		current = append(current, "#"+tag)
+	}
+
+	return m.openInput(label, strings.Join(current, " "), func(value string) tea.Cmd {
+		// Accept bare words as well as hashtags
+		words := strings.Fields(value)
+		for i, word := range words {
+			if !strings.HasPrefix(word, "#") {
+				words[i] = "#" + word
+			}
+		}
+		edited := *note
+		edited.Tags = storage.ParseTags(strings.Join(words, " "))
+
+		return m.runOp(m.updateNoteOp(note, &edited, "tag"), "Updated tags on #%d")
 	})
 }
 
+// updateNoteOp returns an operation that saves an edited copy of a note,
+// undone by writing back the original. Only the fields the edit changed
+// are written, so changes made to the rest of the note since survive.
+func (m *KanbanModel) updateNoteOp(note, edited *storage.Note, verb string) *boardOp {
+	return &boardOp{
+		desc:   fmt.Sprintf("%s #%d", verb, note.ID),
+		noteID: note.ID,
+		undo: func() error {
+			return m.saveChangedFields(note, edited, note)
+		},
+		redo: func() error {
+			return m.saveChangedFields(note, edited, edited)
+		},
+	}
+}
+
+// saveChangedFields saves the note as currently stored with each field
+// that differs between before and after set as in from
+func (m *KanbanModel) saveChangedFields(before, after, from *storage.Note) error {
+	current, err := m.storage.GetNote(before.ID)
+	if err != nil {
+		return err
+	}
+
+	if before.Title != after.Title {
+		current.Title = from.Title
+	}
+	if before.Content != after.Content {
+		current.Content = from.Content
+	}
+	if strings.Join(before.Tags, " ") != strings.Join(after.Tags, " ") {
+		current.Tags = from.Tags
+	}
+	if !sameDate(before.DueAt, after.DueAt) {
+		current.DueAt = from.DueAt
+	}
+	if !sameDate(before.ScheduledAt, after.ScheduledAt) {
+		current.ScheduledAt = from.ScheduledAt
+	}
+	if storage.FormatPriority(before.Priority) != storage.FormatPriority(after.Priority) {
+		current.Priority = from.Priority
+	}
+	if before.Recurrence != after.Recurrence {
+		current.Recurrence = from.Recurrence
+	}
+	if before.Status != after.Status {
+		current.Status = from.Status
+	}
+	return m.storage.SaveNote(current)
+}
+
+// sameDate reports whether two optional dates are both unset or equal
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// confirmDeleteNote asks before deleting the selected note, or every
+// marked note
+func (m *KanbanModel) confirmDeleteNote() {
+	if len(m.marked) > 0 {
+		m.confirmDeleteMarkedNotes()
+		return
+	}
+
+	note := m.selectedNoteOrNil()
+	if note == nil {
+		return
+	}
+
+	m.confirm = &confirmation{
+		prompt: fmt.Sprintf("Delete #%d?", note.ID),
+		action: func() tea.Cmd {
+			// Undo restores the note as deleted, embedding included
+			var deleted []*storage.Note
+			return m.runOp(&boardOp{
+				desc:   fmt.Sprintf("delete #%d", note.ID),
+				noteID: note.ID,
+				undo: func() error {
+					return m.storage.RestoreNotes(deleted)
+				},
+				redo: func() (err error) {
+					deleted, err = m.storage.DeleteNotes([]int{note.ID})
+					return err
+				},
+			}, "Deleted #%d")
+		},
+	}
+}
+
 // refresh reloads data from storage
 func (m *KanbanModel) refresh() tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		err := m.loadNotes()
-		if err != nil {
-			return err
-		}
 		return refreshMsg{}
 	})
 }
 
-// refreshMsg is a custom message for refreshing the view
-type refreshMsg struct{}
+// refreshMsg is a custom message for refreshing the view. A storage
+// operation can set focusNoteID and status to report what it changed, and
+// op to record itself for undo.
+type refreshMsg struct {
+	focusNoteID int
+	status      string
+	op          *boardOp
+}
+
+// boardLayout holds the dimensions the board is rendered with
+type boardLayout struct {
+	columnWidth int // Width of each column, excluding its border
+	sideWidth   int // Width of the detail pane beside the columns, 0 if below or hidden
+	rows        int // Number of cards visible in each column
+}
+
+// layout sizes the columns and detail pane to the terminal
+func (m *KanbanModel) layout() boardLayout {
+	// Reserve room for the detail pane beside the columns when it fits
+	boardWidth := m.width
+	sideWidth := 0
+	if m.showDetail && m.width > 0 {
+		if side := m.width / 3; m.width-side >= minBoardWidth {
+			sideWidth = side
+			boardWidth -= side + 1
+		}
+	}
 
-// renderKanbanBoard renders the kanban board with current state
-func (m *KanbanModel) renderKanbanBoard() string {
 	// Calculate column width based on terminal width
 	columnWidth := 30
-	if m.width > 0 {
-		columnWidth = (m.width - 10) / 3 // Leave some margin
+	if boardWidth > 0 {
+		columnWidth = (boardWidth - 10) / 3 // Leave some margin
 		if columnWidth < 25 {
 			columnWidth = 25
 		}
@@ -214,17 +903,52 @@ func (m *KanbanModel) renderKanbanBoard() string {
 		}
 	}
 
+	if m.height == 0 {
+		return boardLayout{columnWidth: columnWidth, sideWidth: sideWidth, rows: defaultColumnRows}
+	}
+
+	// Everything that isn't a card row: title, headers, the column frame and
+	// scroll indicators, status line, instructions and the blank lines between
+	chrome := lipgloss.Height(m.renderTitle()) + 1 +
+		lipgloss.Height(m.renderColumnHeaders(columnWidth)) + 1 +
+		active.BorderStyle.GetVerticalFrameSize() + 2 +
+		1 + 1 + lipgloss.Height(m.renderInstructions(3*(columnWidth+2)))
+	if m.showDetail && sideWidth == 0 {
+		chrome += lipgloss.Height(renderNoteDetail(m.selectedNoteOrNil(), 3*(columnWidth+2)))
+	}
+
+	rows := m.height - chrome
+	if rows < minColumnRows {
+		rows = minColumnRows
+	}
+
+	return boardLayout{columnWidth: columnWidth, sideWidth: sideWidth, rows: rows}
+}
+
+// renderKanbanBoard renders the kanban board with current state
+func (m *KanbanModel) renderKanbanBoard() string {
+	layout := m.layout()
+
 	// Render title
-	title := titleStyle.Render("📊 Cheesebox Kanban Board")
+	title := m.renderTitle()
 	
 	// Render column headers
-	headers := m.renderColumnHeaders()
+	headers := m.renderColumnHeaders(layout.columnWidth)
 	
-	// Render columns
-	columns := m.renderColumns(columnWidth)
+	// Render columns, with the detail pane beside or below them
+	columns := m.renderColumns(layout.columnWidth, layout.rows)
+	if m.showDetail {
+		selected := m.selectedNoteOrNil()
+		if layout.sideWidth > 0 {
+			columns = lipgloss.JoinHorizontal(lipgloss.Top, columns, " ", renderNoteDetail(selected, layout.sideWidth))
+		} else {
+			columns = lipgloss.JoinVertical(lipgloss.Left, columns, renderNoteDetail(selected, lipgloss.Width(columns)))
+		}
+	}
 	
-	// Render instructions
-	instructions := m.renderInstructions()
+	// Render status line and instructions
+	status := m.renderStatusLine()
+	instructions := m.renderInstructions(lipgloss.Width(columns))
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -233,74 +957,172 @@ func (m *KanbanModel) renderKanbanBoard() string {
 		headers,
 		"",
 		columns,
+		status,
 		"",
 		instructions,
 	)
 }
 
-// renderColumnHeaders renders the column headers with counts
-func (m *KanbanModel) renderColumnHeaders() string {
+// renderTitle renders the board title, and the running timer if any
+func (m *KanbanModel) renderTitle() string {
+	title := active.TitleStyle.Render("📊 Cheesebox Kanban Board")
+	if m.timer == nil {
+		return title
+	}
+
+	timer := fmt.Sprintf("  ◷ #%d %s", m.timer.NoteID, FormatTracked(m.timer.Duration(time.Now())))
+	return lipgloss.JoinHorizontal(lipgloss.Top, title, active.MutedStyle.Render(timer))
+}
+
+// renderColumnHeaders renders the column headers with counts, aligned
+// above columns of the given width. Columns with a WIP limit show
+// count/limit, in a warning style once over it. While filtering, counts
+// read "shown of total".
+func (m *KanbanModel) renderColumnHeaders(columnWidth int) string {
 	columns := []string{"📝 TODO", "⚡ DOING", "✅ DONE"}
-	columnCounts := []int{len(m.todoNotes), len(m.doingNotes), len(m.doneNotes)}
 	
 	var headers []string
 	for i, col := range columns {
-		header := fmt.Sprintf("%s (%d)", col, columnCounts[i])
+		total := len(m.allNotesForColumn(i))
+		limit := m.wipLimits[m.getStatusForColumn(i)]
+		
+		count := fmt.Sprintf("%d", total)
+		if limit > 0 {
+			count = fmt.Sprintf("%d/%d", total, limit)
+		}
+		if m.filter != "" {
+			count = fmt.Sprintf("%d of %s", len(m.getNotesForColumn(i)), count)
+		}
+		header := fmt.Sprintf("%s (%s)", col, count)
 		
-		style := headerStyle
+		style := active.HeaderStyle
 		if i == m.selectedColumn {
-			style = highlightStyle
+			style = active.HighlightStyle
+		}
+		if limit > 0 && total > limit {
+			style = active.WIPExceededStyle
+			if i == m.selectedColumn {
+				style = active.WIPExceededHighlightStyle
+			}
 		}
 		
-		headers = append(headers, style.Render(header))
+		headers = append(headers, lipgloss.NewStyle().Width(columnWidth+2).Render(style.Render(header)))
 	}
 	
 	return lipgloss.JoinHorizontal(lipgloss.Top, headers...)
 }
 
 // renderColumns renders all three kanban columns side by side
-func (m *KanbanModel) renderColumns(columnWidth int) string {
-	todoCol := m.renderColumn(m.todoNotes, 0, columnWidth)
-	doingCol := m.renderColumn(m.doingNotes, 1, columnWidth)
-	doneCol := m.renderColumn(m.doneNotes, 2, columnWidth)
+func (m *KanbanModel) renderColumns(columnWidth, rows int) string {
+	if m.laneMode != laneNone {
+		return m.renderLaneColumns(columnWidth, rows)
+	}
+	
+	todoCol := m.renderColumn(m.getNotesForColumn(0), 0, columnWidth, rows)
+	doingCol := m.renderColumn(m.getNotesForColumn(1), 1, columnWidth, rows)
+	doneCol := m.renderColumn(m.getNotesForColumn(2), 2, columnWidth, rows)
 	
 	return lipgloss.JoinHorizontal(lipgloss.Top, todoCol, doingCol, doneCol)
 }
 
-// renderColumn renders a single kanban column
-func (m *KanbanModel) renderColumn(notes []*storage.Note, columnIndex, width int) string {
-	const maxHeight = 20
+// renderColumn renders a single kanban column showing rows cards from its
+// scroll offset, with indicators for the cards above and below
+func (m *KanbanModel) renderColumn(notes []*storage.Note, columnIndex, width, rows int) string {
+	offset := m.scroll[columnIndex]
+	end := offset + rows
+	if end > len(notes) {
+		end = len(notes)
+	}
+	
+	var cards []string
+	for i := offset; i < end; i++ {
+		cards = append(cards, m.renderCard(notes[i], columnIndex, i, width))
+	}
 	
+	return m.renderColumnFrame(cards, columnIndex, width, rows, offset, len(notes)-end)
+}
+
+// renderCard renders the card at index in a column, truncated to fit
+func (m *KanbanModel) renderCard(note *storage.Note, columnIndex, index, width int) string {
+	prefix := fmt.Sprintf("#%d ", note.ID)
+	if m.marked[note.ID] {
+		prefix = "✓ " + prefix
+	}
+	if m.timer != nil && m.timer.NoteID == note.ID {
+		prefix += "◷ "
+	}
+	if note.Recurrence != "" {
+		prefix += "↻ "
+	}
+	badge := storage.FormatPriority(note.Priority)
+	if badge != "" {
+		badge += " "
+	}
+	// Rolled-up subtask progress, then checklist progress
+	var badges, renderedBadges string
+	if p := m.progress[note.ID]; p.Total > 0 {
+		badges += fmt.Sprintf("◆ %d/%d ", p.Done, p.Total)
+		renderedBadges += renderProgress(p.Done, p.Total, "◆ ") + " "
+	}
+	if checked, total := storage.ChecklistProgress(note.Content); total > 0 {
+		badges += fmt.Sprintf("%d/%d ", checked, total)
+		renderedBadges += renderProgress(checked, total, "") + " "
+	}
+	content := note.Title
+	noteText := truncate(prefix+badge+badges+content, width-6)
+	
+	// Highlight selected note
+	if columnIndex == m.selectedColumn && index == m.selectedNote {
+		return active.HighlightStyle.Render(noteText)
+	}
+	
+	style := active.ContentStyle
+	switch {
+	case m.marked[note.ID]:
+		style = active.MarkedStyle
+	case note.IsOverdue(time.Now()):
+		style = active.ErrorStyle
+	}
+	
+	// Colour the badges unless the card is too narrow to show them
+	if !strings.HasPrefix(noteText, prefix) {
+		return style.Render(noteText)
+	}
+	rendered, rest := style.Render(prefix), strings.TrimPrefix(noteText, prefix)
+	if badge != "" && strings.HasPrefix(rest, badge) {
+		rendered += priorityStyle(*note.Priority).Render(badge)
+		rest = strings.TrimPrefix(rest, badge)
+	}
+	if badges != "" && strings.HasPrefix(rest, badges) {
+		rendered += renderedBadges
+		rest = strings.TrimPrefix(rest, badges)
+	}
+	return rendered + style.Render(rest)
+}
+
+// renderColumnFrame draws a column border around rows lines of content,
+// with indicators for the cards scrolled out of view above and below
+func (m *KanbanModel) renderColumnFrame(lines []string, columnIndex, width, rows, above, below int) string {
 	var content []string
 	
-	for i, note := range notes {
-		if i >= maxHeight-1 { // Leave space for "..." indicator
-			content = append(content, mutedStyle.Render("..."))
-			break
-		}
-		
-		// Truncate content to fit column
-		noteContent := note.Content
-		maxContentWidth := width - 8 // Account for padding and ID
-		if len(noteContent) > maxContentWidth {
-			noteContent = noteContent[:maxContentWidth-3] + "..."
-		}
-		
-		// Format note
-		noteText := fmt.Sprintf("#%d %s", note.ID, noteContent)
-		
-		// Highlight selected note
-		if columnIndex == m.selectedColumn && i == m.selectedNote {
-			noteText = highlightStyle.Render(noteText)
-		} else {
-			noteText = contentStyle.Render(noteText)
-		}
-		
-		content = append(content, noteText)
+	// Cards above the viewport
+	if above > 0 {
+		content = append(content, active.MutedStyle.Render(fmt.Sprintf("↑ %d more", above)))
+	} else {
+		content = append(content, "")
 	}
 	
+	content = append(content, lines...)
+	
 	// Fill remaining space
-	for len(content) < maxHeight {
+	for len(content) < rows+1 {
+		content = append(content, "")
+	}
+	
+	// Cards below the viewport
+	if below > 0 {
+		content = append(content, active.MutedStyle.Render(fmt.Sprintf("↓ %d more", below)))
+	} else {
 		content = append(content, "")
 	}
 	
@@ -308,23 +1130,91 @@ func (m *KanbanModel) renderColumn(notes []*storage.Note, columnIndex, width int
 	columnContent := lipgloss.JoinVertical(lipgloss.Left, content...)
 	
 	// Style the column
-	style := borderStyle.Width(width).Height(maxHeight + 2)
+	style := active.BorderStyle.Copy().Width(width)
 	if columnIndex == m.selectedColumn {
-		style = style.BorderForeground(primaryColor)
+		style = style.BorderForeground(active.Primary)
 	}
 	
 	return style.Render(columnContent)
 }
 
-// renderInstructions renders the control instructions
-func (m *KanbanModel) renderInstructions() string {
-	instructions := []string{
-		"← → or h l: Navigate columns",
-		"↑ ↓ or k j: Select notes",
-		"Enter/Space: Move note",
-		"r: Refresh",
-		"q: Quit",
+// scrollToSelection adjusts the selected column's scroll offset, or the
+// shared offset in swimlane mode, so the selected card is visible
+func (m *KanbanModel) scrollToSelection() {
+	rows := m.layout().rows
+
+	if m.laneMode != laneNone {
+		boardRows := m.laneRows()
+		m.laneScroll = scrollOffset(m.laneScroll, m.selectedLaneRow(boardRows), rows, len(boardRows))
+		return
 	}
-	
-	return mutedStyle.Render(lipgloss.JoinVertical(lipgloss.Left, instructions...))
+
+	notes := m.getNotesForColumn(m.selectedColumn)
+	m.scroll[m.selectedColumn] = scrollOffset(m.scroll[m.selectedColumn], m.selectedNote, rows, len(notes))
+}
+
+// scrollOffset returns the offset that keeps line visible in a viewport of
+// rows lines over total lines, moving the current offset as little as possible
+func scrollOffset(offset, line, rows, total int) int {
+	if line >= 0 && line < offset {
+		offset = line
+	}
+	if line >= offset+rows {
+		offset = line - rows + 1
+	}
+	return clampOffset(offset, rows, total)
+}
+
+// firstVisibleNote returns the index of the first card scrolled into view
+// in a column
+func (m *KanbanModel) firstVisibleNote(column int) int {
+	if m.laneMode == laneNone {
+		return m.scroll[column]
+	}
+
+	index := 0
+	for i, row := range m.laneRows() {
+		notes := row.lane.notes[column]
+		if row.header || row.index >= len(notes) {
+			continue
+		}
+		if i >= m.laneScroll {
+			return index
+		}
+		index++
+	}
+	return 0
+}
+
+// selectNote moves the selection within the current column by delta cards,
+// clamped to the column
+func (m *KanbanModel) selectNote(delta int) {
+	notes := m.getNotesForColumn(m.selectedColumn)
+	m.selectedNote += delta
+	if m.selectedNote >= len(notes) {
+		m.selectedNote = len(notes) - 1
+	}
+	if m.selectedNote < 0 {
+		m.selectedNote = 0
+	}
+	m.scrollToSelection()
+}
+
+// renderStatusLine renders the active prompt or the last status message
+func (m *KanbanModel) renderStatusLine() string {
+	if prompt := m.promptView(); prompt != "" {
+		return prompt
+	}
+
+	var parts []string
+	if m.filter != "" {
+		parts = append(parts, active.LabelStyle.Render("Filter:")+" "+m.filter+active.MutedStyle.Render(" (/ to edit, Esc to clear)"))
+	}
+	if len(m.marked) > 0 {
+		parts = append(parts, active.LabelStyle.Render(fmt.Sprintf("%d selected", len(m.marked)))+active.MutedStyle.Render(" (Esc to clear)"))
+	}
+	if m.statusMsg != "" {
+		parts = append(parts, active.MutedStyle.Render(m.statusMsg))
+	}
+	return strings.Join(parts, active.MutedStyle.Render(" • "))
 }
\ No newline at end of file
@@ -0,0 +1,285 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// keyMap holds the kanban board's key bindings
+type keyMap struct {
+	Left     key.Binding
+	Right    key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+
+	MoveNext  key.Binding
+	MoveLeft  key.Binding
+	MoveRight key.Binding
+	MoveTodo  key.Binding
+	MoveDoing key.Binding
+	MoveDone  key.Binding
+
+	Add     key.Binding
+	Edit    key.Binding
+	Tags    key.Binding
+	Delete  key.Binding
+	Mark    key.Binding
+	Archive key.Binding
+	Timer   key.Binding
+	Check   key.Binding
+	Undo    key.Binding
+	Redo    key.Binding
+
+	Details      key.Binding
+	Filter       key.Binding
+	Clear        key.Binding
+	Swimlanes    key.Binding
+	CollapseLane key.Binding
+	ExpandLanes  key.Binding
+	Refresh      key.Binding
+	Help         key.Binding
+	Quit         key.Binding
+}
+
+// keySection is a titled group of bindings in the help overlay
+type keySection struct {
+	title    string
+	bindings []namedBinding
+}
+
+// namedBinding is a binding with the action name used to override it
+// from the config file
+type namedBinding struct {
+	name    string
+	binding *key.Binding
+}
+
+// binding creates a key binding whose help lists its keys
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKeys(keys), desc))
+}
+
+// defaultKeyMap returns the default key bindings
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Left:     binding("Previous column", "left", "h"),
+		Right:    binding("Next column", "right", "l"),
+		Up:       binding("Previous card", "up", "k"),
+		Down:     binding("Next card", "down", "j"),
+		PageUp:   binding("Page up", "pgup", "ctrl+u"),
+		PageDown: binding("Page down", "pgdown", "ctrl+d"),
+		Top:      binding("First card", "g", "home"),
+		Bottom:   binding("Last card", "G", "end"),
+
+		MoveNext:  binding("Move card forward", "enter", " "),
+		MoveLeft:  binding("Move card left", "H", "shift+left"),
+		MoveRight: binding("Move card right", "L", "shift+right"),
+		MoveTodo:  binding("Move card to TODO", "1"),
+		MoveDoing: binding("Move card to DOING", "2"),
+		MoveDone:  binding("Move card to DONE", "3"),
+
+		Add:     binding("Add card", "a"),
+		Edit:    binding("Edit card", "e"),
+		Tags:    binding("Edit tags", "t"),
+		Delete:  binding("Delete card", "d"),
+		Mark:    binding("Mark card", "v", "x"),
+		Archive: binding("Archive card", "A"),
+		Timer:   binding("Start/stop timer", "T"),
+		Check:   binding("Toggle checklist item", "c"),
+		Undo:    binding("Undo", "u"),
+		Redo:    binding("Redo", "ctrl+r"),
+
+		Details:      binding("Toggle card details", "i"),
+		Filter:       binding("Filter (text, #tag, priority:p1, sort:priority)", "/"),
+		Clear:        binding("Clear selection or filter", "esc"),
+		Swimlanes:    binding("Cycle swimlanes", "s"),
+		CollapseLane: binding("Collapse or expand lane", "z"),
+		ExpandLanes:  binding("Expand all lanes", "Z"),
+		Refresh:      binding("Refresh", "r"),
+		Help:         binding("Help", "?"),
+		Quit:         binding("Quit", "q", "ctrl+c"),
+	}
+}
+
+// sections groups the bindings for the help overlay, naming each action
+// as it is written in the config file's "keys" object
+func (k *keyMap) sections() []keySection {
+	return []keySection{
+		{"Navigation", []namedBinding{
+			{"left", &k.Left}, {"right", &k.Right}, {"up", &k.Up}, {"down", &k.Down},
+			{"page_up", &k.PageUp}, {"page_down", &k.PageDown}, {"top", &k.Top}, {"bottom", &k.Bottom},
+		}},
+		{"Moving cards", []namedBinding{
+			{"move_next", &k.MoveNext}, {"move_left", &k.MoveLeft}, {"move_right", &k.MoveRight},
+			{"move_todo", &k.MoveTodo}, {"move_doing", &k.MoveDoing}, {"move_done", &k.MoveDone},
+		}},
+		{"Editing", []namedBinding{
+			{"add", &k.Add}, {"edit", &k.Edit}, {"tags", &k.Tags}, {"delete", &k.Delete},
+			{"mark", &k.Mark}, {"archive", &k.Archive}, {"timer", &k.Timer}, {"check", &k.Check}, {"undo", &k.Undo}, {"redo", &k.Redo},
+		}},
+		{"View", []namedBinding{
+			{"details", &k.Details}, {"filter", &k.Filter}, {"clear", &k.Clear},
+			{"swimlanes", &k.Swimlanes}, {"collapse_lane", &k.CollapseLane}, {"expand_lanes", &k.ExpandLanes},
+			{"refresh", &k.Refresh}, {"help", &k.Help}, {"quit", &k.Quit},
+		}},
+	}
+}
+
+// shortHelp returns the bindings listed below the board
+func (k *keyMap) shortHelp() []key.Binding {
+	return []key.Binding{
+		k.Left, k.Right, k.Up, k.Down, k.MoveNext, k.Add, k.Edit, k.Delete,
+		k.Mark, k.Filter, k.Undo, k.Help, k.Quit,
+	}
+}
+
+// newKeyMap returns the default bindings with overrides applied.
+// overrides maps an action name to the keys that trigger it; an empty
+// list unbinds the action. It fails on unknown actions and on keys bound
+// to more than one action.
+func newKeyMap(overrides map[string][]string) (keyMap, error) {
+	keys := defaultKeyMap()
+
+	byName := make(map[string]*key.Binding)
+	for _, section := range keys.sections() {
+		for _, nb := range section.bindings {
+			byName[nb.name] = nb.binding
+		}
+	}
+
+	for name, override := range overrides {
+		b, ok := byName[name]
+		if !ok {
+			return keyMap{}, fmt.Errorf("unknown key binding action %q", name)
+		}
+
+		var normalized []string
+		for _, k := range override {
+			if k == "space" {
+				k = " "
+			}
+			normalized = append(normalized, k)
+		}
+
+		if len(normalized) == 0 {
+			b.Unbind()
+			continue
+		}
+		b.SetKeys(normalized...)
+		b.SetHelp(helpKeys(normalized), b.Help().Desc)
+	}
+
+	// Report keys bound twice in a stable order
+	owner := make(map[string]string)
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, k := range byName[name].Keys() {
+			if other, ok := owner[k]; ok {
+				return keyMap{}, fmt.Errorf("key %q is bound to both %s and %s", k, other, name)
+			}
+			owner[k] = name
+		}
+	}
+
+	return keys, nil
+}
+
+// keySymbols are the names shown in help for keys with a symbol
+var keySymbols = map[string]string{
+	"left":        "←",
+	"right":       "→",
+	"up":          "↑",
+	"down":        "↓",
+	"shift+left":  "⇧←",
+	"shift+right": "⇧→",
+	"shift+up":    "⇧↑",
+	"shift+down":  "⇧↓",
+	"shift+tab":   "⇧tab",
+	" ":           "space",
+}
+
+// helpKeys formats keys for help text, e.g. "←/h"
+func helpKeys(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k
+		if symbol, ok := keySymbols[k]; ok {
+			names[i] = symbol
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// renderInstructions renders the most used bindings, wrapped to width
+func (m *KanbanModel) renderInstructions(width int) string {
+	var instructions []string
+	for _, b := range m.keys.shortHelp() {
+		if b.Enabled() {
+			instructions = append(instructions, b.Help().Key+": "+b.Help().Desc)
+		}
+	}
+
+	return active.MutedStyle.Render(wordwrap.String(strings.Join(instructions, " • "), width))
+}
+
+// renderHelp renders the full-screen help overlay listing every binding,
+// with sections packed side by side as the width allows
+func (m *KanbanModel) renderHelp() string {
+	var blocks []string
+	for _, section := range m.keys.sections() {
+		keyWidth := 0
+		for _, nb := range section.bindings {
+			if w := lipgloss.Width(nb.binding.Help().Key); w > keyWidth {
+				keyWidth = w
+			}
+		}
+
+		lines := []string{active.LabelStyle.Render(section.title)}
+		for _, nb := range section.bindings {
+			help := nb.binding.Help()
+			keys := help.Key
+			if !nb.binding.Enabled() {
+				keys = "unbound"
+			}
+			lines = append(lines, active.KeyStyle.Copy().Width(keyWidth+2).Render(keys)+
+				active.ContentStyle.Render(help.Desc)+active.MutedStyle.Render(" ("+nb.name+")"))
+		}
+		blocks = append(blocks, lipgloss.NewStyle().MarginRight(4).MarginBottom(1).Render(strings.Join(lines, "\n")))
+	}
+
+	// Pack the sections into rows that fit the terminal
+	var rows []string
+	var row []string
+	rowWidth := 0
+	for _, block := range blocks {
+		w := lipgloss.Width(block)
+		if len(row) > 0 && m.width > 0 && rowWidth+w > m.width {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, rowWidth = nil, 0
+		}
+		row = append(row, block)
+		rowWidth += w
+	}
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		active.TitleStyle.Render("⌨️  Keybindings"),
+		lipgloss.JoinVertical(lipgloss.Left, rows...),
+		active.MutedStyle.Render(fmt.Sprintf("Override keys by name in the \"keys\" object of ~/.cheesebox/config.json • %s or %s to close",
+			m.keys.Help.Help().Key, m.keys.Clear.Help().Key)),
+	)
+}
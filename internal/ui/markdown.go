@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// buildMarkdownStyles builds the theme's markdown styles from its colours
+func (t *Theme) buildMarkdownStyles() {
+	t.MarkdownHeadingStyle = lipgloss.NewStyle().
+		Foreground(t.Primary).
+		Bold(true)
+
+	t.MarkdownCodeStyle = lipgloss.NewStyle().
+		Foreground(t.Secondary)
+
+	t.MarkdownQuoteStyle = lipgloss.NewStyle().
+		Foreground(t.Muted).
+		Italic(true)
+
+	t.MarkdownBoldStyle = lipgloss.NewStyle().Bold(true)
+
+	t.MarkdownItalicStyle = lipgloss.NewStyle().Italic(true)
+}
+
+var (
+	mdHeadingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdCheckboxPattern = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	mdBulletPattern   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdNumberedPattern = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	mdInlineCode      = regexp.MustCompile("`([^`]+)`")
+	mdBold            = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalic          = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+)
+
+// renderMarkdown renders the subset of markdown that shows up in notes
+// (headings, lists, checkboxes, quotes, code and emphasis), wrapped to width
+func renderMarkdown(text string, width int) string {
+	if width < 10 {
+		width = 10
+	}
+
+	var lines []string
+	inCode := false
+	item := 0 // Checklist items are numbered for cx check and the c key
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, active.MarkdownCodeStyle.Render("  "+line))
+			continue
+		}
+
+		switch {
+		case mdHeadingPattern.MatchString(trimmed):
+			match := mdHeadingPattern.FindStringSubmatch(trimmed)
+			lines = append(lines, active.MarkdownHeadingStyle.Render(wordwrap.String(match[2], width)))
+
+		case mdCheckboxPattern.MatchString(line):
+			match := mdCheckboxPattern.FindStringSubmatch(line)
+			box := "☐"
+			if match[2] != " " {
+				box = "☑"
+			}
+			item++
+			prefix := match[1] + active.MutedStyle.Render(fmt.Sprintf("%d.", item)) + " " + box + " "
+			lines = append(lines, wrapItem(prefix, renderInline(match[3]), width))
+
+		case mdBulletPattern.MatchString(line):
+			match := mdBulletPattern.FindStringSubmatch(line)
+			lines = append(lines, wrapItem(match[1]+"• ", renderInline(match[2]), width))
+
+		case mdNumberedPattern.MatchString(line):
+			match := mdNumberedPattern.FindStringSubmatch(line)
+			lines = append(lines, wrapItem(match[1]+match[2]+" ", renderInline(match[3]), width))
+
+		case strings.HasPrefix(trimmed, ">"):
+			quote := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			lines = append(lines, wrapItem("│ ", active.MarkdownQuoteStyle.Render(quote), width))
+
+		default:
+			lines = append(lines, wordwrap.String(renderInline(line), width))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// wrapItem wraps text to width and indents continuation lines under prefix
+func wrapItem(prefix, text string, width int) string {
+	indent := strings.Repeat(" ", lipgloss.Width(prefix))
+	wrapped := wordwrap.String(text, width-len(indent))
+	return prefix + strings.ReplaceAll(wrapped, "\n", "\n"+indent)
+}
+
+// renderInline applies inline code, bold and italic styling
+func renderInline(text string) string {
+	text = mdInlineCode.ReplaceAllStringFunc(text, func(s string) string {
+		return active.MarkdownCodeStyle.Render(strings.Trim(s, "`"))
+	})
+	text = mdBold.ReplaceAllStringFunc(text, func(s string) string {
+		return active.MarkdownBoldStyle.Render(s[2 : len(s)-2])
+	})
+	text = mdItalic.ReplaceAllStringFunc(text, func(s string) string {
+		return active.MarkdownItalicStyle.Render(s[1 : len(s)-1])
+	})
+	return text
+}
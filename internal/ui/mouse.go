@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"cheesebox/internal/storage"
+)
+
+// wheelStep is how many cards one notch of the mouse wheel scrolls
+const wheelStep = 3
+
+// dragState tracks a card being dragged to another column
+type dragState struct {
+	note   *storage.Note
+	column int // Column the drag started in
+}
+
+// boardHit describes what is under a mouse position on the board
+type boardHit struct {
+	column int
+	header bool      // The column header
+	card   int       // Index of the card in the column, or -1
+	lane   *swimlane // Lane whose header row was hit, if any
+}
+
+// hitTest maps a mouse position to the part of the board under it. It
+// mirrors the vertical layout of renderKanbanBoard: title, blank line,
+// column headers, blank line, then the column frames.
+func (m *KanbanModel) hitTest(x, y int) (boardHit, bool) {
+	layout := m.layout()
+	column := x / (layout.columnWidth + 2)
+	if x < 0 || column >= numColumns {
+		return boardHit{}, false
+	}
+	hit := boardHit{column: column, card: -1}
+
+	headersTop := lipgloss.Height(m.renderTitle()) + 1
+	headersHeight := lipgloss.Height(m.renderColumnHeaders(layout.columnWidth))
+	if y >= headersTop && y < headersTop+headersHeight {
+		hit.header = true
+		return hit, true
+	}
+
+	// Card rows start below the frame's top border, its padding and the
+	// "more above" indicator line
+	firstRow := headersTop + headersHeight + 1 +
+		active.BorderStyle.GetBorderTopSize() + active.BorderStyle.GetPaddingTop() + 1
+	row := y - firstRow
+	if row < 0 || row >= layout.rows {
+		return hit, true
+	}
+
+	if m.laneMode == laneNone {
+		if index := m.scroll[column] + row; index < len(m.getNotesForColumn(column)) {
+			hit.card = index
+		}
+		return hit, true
+	}
+
+	boardRows := m.laneRows()
+	i := m.laneScroll + row
+	if i >= len(boardRows) {
+		return hit, true
+	}
+	if boardRows[i].header {
+		hit.lane = boardRows[i].lane
+		return hit, true
+	}
+	if boardRows[i].index < len(boardRows[i].lane.notes[column]) {
+		// Count the column's cards in the rows above to find its index
+		index := 0
+		for _, above := range boardRows[:i] {
+			if !above.header && above.index < len(above.lane.notes[column]) {
+				index++
+			}
+		}
+		hit.card = index
+	}
+	return hit, true
+}
+
+// handleMouse selects cards and columns on click, scrolls columns with the
+// wheel and moves a card dragged onto another column
+func (m *KanbanModel) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	// Prompts take all input until they are answered
+	if m.promptOpen() {
+		return nil
+	}
+
+	hit, ok := m.hitTest(msg.X, msg.Y)
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelDown:
+		if ok {
+			delta := wheelStep
+			if msg.Button == tea.MouseButtonWheelUp {
+				delta = -wheelStep
+			}
+			m.scrollColumn(hit.column, delta)
+		}
+
+	case msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft:
+		m.drag = nil
+		if !ok {
+			return nil
+		}
+		switch {
+		case hit.card >= 0:
+			m.selectedColumn = hit.column
+			m.selectedNote = hit.card
+			if note := m.selectedNoteOrNil(); note != nil {
+				m.drag = &dragState{note: note, column: hit.column}
+			}
+		case hit.lane != nil:
+			if selected := m.selectedNoteOrNil(); selected != nil {
+				m.focusNoteID = selected.ID
+			}
+			m.toggleLane(hit.lane.name)
+		case hit.header:
+			m.selectedColumn = hit.column
+			m.selectedNote = m.firstVisibleNote(hit.column)
+			m.selectNote(0)
+		}
+
+	case msg.Action == tea.MouseActionMotion && m.drag != nil:
+		if ok && hit.column != m.drag.column {
+			m.statusMsg = fmt.Sprintf("Drop #%d on %s", m.drag.note.ID, strings.ToUpper(m.getStatusForColumn(hit.column)))
+		} else {
+			m.statusMsg = ""
+		}
+
+	case msg.Action == tea.MouseActionRelease && m.drag != nil:
+		drag := m.drag
+		m.drag = nil
+		if !ok || hit.column == drag.column {
+			return nil
+		}
+		// Dropping a marked card moves every marked card, like the move keys
+		if m.marked[drag.note.ID] {
+			return m.moveMarkedNotesTo(hit.column)
+		}
+		return m.moveNoteTo(drag.note, hit.column)
+	}
+
+	return nil
+}
+
+// scrollColumn scrolls a column by delta cards without moving the
+// selection, or every column together in swimlane mode
+func (m *KanbanModel) scrollColumn(column, delta int) {
+	rows := m.layout().rows
+
+	if m.laneMode != laneNone {
+		m.laneScroll = clampOffset(m.laneScroll+delta, rows, len(m.laneRows()))
+		return
+	}
+	m.scroll[column] = clampOffset(m.scroll[column]+delta, rows, len(m.getNotesForColumn(column)))
+}
+
+// clampOffset keeps a scroll offset within a viewport of rows lines over
+// total lines
+func clampOffset(offset, rows, total int) int {
+	if maxOffset := total - rows; offset > maxOffset {
+		offset = maxOffset
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+)
+
+// prompt is the inline text input and yes/no confirmation shared by the
+// interactive views, shown on their status line. At most one is open, and
+// while it is it takes every key press.
+type prompt struct {
+	confirm *confirmation // Pending yes/no prompt, if any
+	input   *inputPrompt  // Active inline text input, if any
+}
+
+// confirmation is a yes/no prompt that runs action when accepted
+type confirmation struct {
+	prompt string
+	action func() tea.Cmd
+}
+
+// inputPrompt is an inline text input that runs submit with the entered
+// value. The optional onChange runs on every edit and cancel on Esc.
+type inputPrompt struct {
+	label    string
+	field    textinput.Model
+	submit   func(value string) tea.Cmd
+	onChange func(value string) tea.Cmd
+	cancel   func()
+}
+
+// promptOpen reports whether a prompt is waiting for an answer
+func (p *prompt) promptOpen() bool {
+	return p.confirm != nil || p.input != nil
+}
+
+// openInput shows an inline text input pre-filled with value
+func (p *prompt) openInput(label, value string, submit func(value string) tea.Cmd) tea.Cmd {
+	field := textinput.New()
+	field.Prompt = "> "
+	field.CharLimit = 0
+	field.SetValue(value)
+	field.CursorEnd()
+	cmd := field.Focus()
+
+	p.input = &inputPrompt{label: label, field: field, submit: submit}
+	return cmd
+}
+
+// handlePromptKey routes a key press to the open prompt, reporting
+// whether it was Ctrl+C, which quits. Cancelling the prompt sets status
+// to "Cancelled" before the input's cancel hook runs, so the hook can
+// replace it.
+func (p *prompt) handlePromptKey(msg tea.KeyMsg, status *string) (tea.Cmd, bool) {
+	if msg.Type == tea.KeyCtrlC {
+		p.confirm, p.input = nil, nil
+		return tea.Quit, true
+	}
+
+	if confirm := p.confirm; confirm != nil {
+		p.confirm = nil
+		if msg.String() == "y" || msg.String() == "Y" {
+			return confirm.action(), false
+		}
+		*status = "Cancelled"
+		return nil, false
+	}
+
+	input := p.input
+	switch msg.Type {
+	case tea.KeyEnter:
+		p.input = nil
+		return input.submit(strings.TrimSpace(input.field.Value())), false
+	case tea.KeyEsc:
+		p.input = nil
+		*status = "Cancelled"
+		if input.cancel != nil {
+			input.cancel()
+		}
+		return nil, false
+	}
+
+	before := input.field.Value()
+	var cmd tea.Cmd
+	input.field, cmd = input.field.Update(msg)
+	if value := input.field.Value(); value != before && input.onChange != nil {
+		cmd = tea.Batch(cmd, input.onChange(value))
+	}
+	return cmd, false
+}
+
+// updatePrompt passes other messages to the text input, keeping its
+// cursor blinking
+func (p *prompt) updatePrompt(msg tea.Msg) tea.Cmd {
+	if p.input == nil {
+		return nil
+	}
+	var cmd tea.Cmd
+	p.input.field, cmd = p.input.field.Update(msg)
+	return cmd
+}
+
+// promptView renders the open prompt, or "" when none is
+func (p *prompt) promptView() string {
+	switch {
+	case p.input != nil:
+		return active.LabelStyle.Render(p.input.label) + " " + p.input.field.View()
+	case p.confirm != nil:
+		return active.WarningStyle.Render(p.confirm.prompt + " (y/N)")
+	}
+	return ""
+}
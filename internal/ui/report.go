@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// reportBarWidth is the width of the longest bar in a report
+const reportBarWidth = 30
+
+// TimeReportRow is one group in a time report and the time tracked in it
+type TimeReportRow struct {
+	Label    string
+	Duration time.Duration
+}
+
+// RenderTimeReport renders the time tracked in each group as a bar scaled
+// to the largest, followed by the total
+func RenderTimeReport(title string, rows []TimeReportRow, total time.Duration) string {
+	var output strings.Builder
+	output.WriteString(active.TitleStyle.Render(title))
+	output.WriteString("\n")
+
+	if len(rows) == 0 {
+		output.WriteString(active.MutedStyle.Render("No time tracked. Start a timer with: cx start <id>"))
+		return output.String()
+	}
+
+	labelWidth := 0
+	var longest time.Duration
+	for _, row := range rows {
+		if w := runewidth.StringWidth(row.Label); w > labelWidth {
+			labelWidth = w
+		}
+		if row.Duration > longest {
+			longest = row.Duration
+		}
+	}
+	if labelWidth > 40 {
+		labelWidth = 40
+	}
+
+	for _, row := range rows {
+		label := runewidth.FillRight(runewidth.Truncate(row.Label, labelWidth, "..."), labelWidth)
+		bar := 1
+		if longest > 0 {
+			bar = int(float64(reportBarWidth)*float64(row.Duration)/float64(longest) + 0.5)
+		}
+		if bar < 1 {
+			bar = 1
+		}
+		output.WriteString(fmt.Sprintf("  %s  %s %s\n", active.ContentStyle.Render(label),
+			active.BarStyle.Render(strings.Repeat("█", bar)), active.MutedStyle.Render(FormatTracked(row.Duration))))
+	}
+
+	output.WriteString("\n")
+	output.WriteString(active.LabelStyle.Render("Total: " + FormatTracked(total)))
+	return output.String()
+}
+
+// FormatTracked formats tracked time in hours and minutes, like 2h 05m
+func FormatTracked(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"cheesebox/internal/storage"
+)
+
+// toggleMark marks or unmarks the selected card for bulk actions
+func (m *KanbanModel) toggleMark() {
+	note := m.selectedNoteOrNil()
+	if note == nil {
+		return
+	}
+
+	if m.marked[note.ID] {
+		delete(m.marked, note.ID)
+	} else {
+		m.marked[note.ID] = true
+	}
+}
+
+// clearMarks unmarks every card
+func (m *KanbanModel) clearMarks() {
+	m.marked = make(map[int]bool)
+}
+
+// pruneMarks drops marks on notes that are no longer on the board
+func (m *KanbanModel) pruneMarks() {
+	onBoard := make(map[int]bool)
+	for column := 0; column < numColumns; column++ {
+		for _, note := range m.allNotesForColumn(column) {
+			onBoard[note.ID] = true
+		}
+	}
+	for id := range m.marked {
+		if !onBoard[id] {
+			delete(m.marked, id)
+		}
+	}
+}
+
+// markedNotes returns the marked notes in board order, including marked
+// notes hidden by the filter or a collapsed lane
+func (m *KanbanModel) markedNotes() []*storage.Note {
+	var notes []*storage.Note
+	for column := 0; column < numColumns; column++ {
+		for _, note := range m.allNotesForColumn(column) {
+			if m.marked[note.ID] {
+				notes = append(notes, note)
+			}
+		}
+	}
+	return notes
+}
+
+// targetNotes returns the marked notes, or the selected note when none
+// are marked
+func (m *KanbanModel) targetNotes() []*storage.Note {
+	if len(m.marked) > 0 {
+		return m.markedNotes()
+	}
+	if note := m.selectedNoteOrNil(); note != nil {
+		return []*storage.Note{note}
+	}
+	return nil
+}
+
+// focusID returns the note to select after a bulk operation: the selected
+// note, so the cursor stays put as marked cards move around it
+func (m *KanbanModel) focusID() int {
+	if note := m.selectedNoteOrNil(); note != nil {
+		return note.ID
+	}
+	return 0
+}
+
+// cardCount formats a number of cards, e.g. "1 card" or "3 cards"
+func cardCount(n int) string {
+	if n == 1 {
+		return "1 card"
+	}
+	return fmt.Sprintf("%d cards", n)
+}
+
+// moveMarkedNotesTo moves every marked note to the given column, asking
+// for confirmation like moveSelectedNoteTo does for a single note
+func (m *KanbanModel) moveMarkedNotesTo(column int) tea.Cmd {
+	if column < 0 || column >= numColumns {
+		return nil
+	}
+	newStatus := m.getStatusForColumn(column)
+
+	oldStatuses := make(map[int]string)
+	newStatuses := make(map[int]string)
+	leavingTerminal := 0
+	for _, note := range m.markedNotes() {
+		if note.Status == newStatus {
+			continue
+		}
+		oldStatuses[note.ID] = note.Status
+		newStatuses[note.ID] = newStatus
+		if note.Status == m.getStatusForColumn(terminalColumn) {
+			leavingTerminal++
+		}
+	}
+	if len(newStatuses) == 0 {
+		m.statusMsg = "Marked cards are already in " + strings.ToUpper(newStatus)
+		return nil
+	}
+
+	desc := fmt.Sprintf("move %s to %s", cardCount(len(newStatuses)), strings.ToUpper(newStatus))
+	move := func() tea.Cmd {
+		return m.runOp(&boardOp{
+			desc:   desc,
+			noteID: m.focusID(),
+			undo: func() error {
+				return m.storage.UpdateNotesStatus(oldStatuses)
+			},
+			redo: func() error {
+				return m.storage.UpdateNotesStatus(newStatuses)
+			},
+		}, "Moved "+cardCount(len(newStatuses))+" to "+strings.ToUpper(newStatus))
+	}
+
+	var warnings []string
+	if leavingTerminal > 0 {
+		warnings = append(warnings, fmt.Sprintf("Move %s out of %s to %s?",
+			cardCount(leavingTerminal), strings.ToUpper(m.getStatusForColumn(terminalColumn)), strings.ToUpper(newStatus)))
+	}
+	if m.exceedsWIPLimit(column, len(newStatuses)) {
+		limit := m.wipLimits[newStatus]
+		if m.wipBlock {
+			m.statusMsg = fmt.Sprintf("Moving %s would take %s past its WIP limit (%d)",
+				cardCount(len(newStatuses)), strings.ToUpper(newStatus), limit)
+			return nil
+		}
+		warnings = append(warnings, fmt.Sprintf("%s would exceed its WIP limit (%d/%d). Move %s anyway?",
+			strings.ToUpper(newStatus), len(m.allNotesForColumn(column))+len(newStatuses), limit, cardCount(len(newStatuses))))
+	}
+
+	if len(warnings) > 0 {
+		m.confirm = &confirmation{
+			prompt: strings.Join(warnings, " "),
+			action: move,
+		}
+		return nil
+	}
+
+	return move()
+}
+
+// startTagMarkedNotes prompts for tags to add to every marked note. Words
+// prefixed with - remove the tag instead.
+func (m *KanbanModel) startTagMarkedNotes() tea.Cmd {
+	notes := m.markedNotes()
+	label := fmt.Sprintf("Tags for %s (-tag removes):", cardCount(len(notes)))
+
+	return m.openInput(label, "", func(value string) tea.Cmd {
+		var add, remove []string
+		for _, word := range strings.Fields(value) {
+			target := &add
+			if strings.HasPrefix(word, "-") {
+				target = &remove
+				word = strings.TrimPrefix(word, "-")
+			}
+			if !strings.HasPrefix(word, "#") {
+				word = "#" + word
+			}
+			*target = append(*target, storage.ParseTags(word)...)
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			return nil
+		}
+
+		oldTags := make(map[int][]string)
+		newTags := make(map[int][]string)
+		for _, note := range notes {
+			oldTags[note.ID] = note.Tags
+			newTags[note.ID] = editTags(note.Tags, add, remove)
+		}
+
+		return m.runOp(&boardOp{
+			desc:   "tag " + cardCount(len(notes)),
+			noteID: m.focusID(),
+			undo: func() error {
+				return m.storage.UpdateNotesTags(oldTags)
+			},
+			redo: func() error {
+				return m.storage.UpdateNotesTags(newTags)
+			},
+		}, "Updated tags on "+cardCount(len(notes)))
+	})
+}
+
+// editTags returns tags with add appended, skipping tags already present,
+// and remove taken out
+func editTags(tags, add, remove []string) []string {
+	removed := make(map[string]bool)
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, tag := range append(append([]string{}, tags...), add...) {
+		if removed[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// confirmDeleteMarkedNotes asks before deleting every marked note
+func (m *KanbanModel) confirmDeleteMarkedNotes() {
+	notes := m.markedNotes()
+	ids := noteIDs(notes)
+
+	m.confirm = &confirmation{
+		prompt: fmt.Sprintf("Delete %s?", cardCount(len(notes))),
+		action: func() tea.Cmd {
+			// Undo restores the notes as deleted, embeddings included
+			var deleted []*storage.Note
+			return m.runOp(&boardOp{
+				desc: "delete " + cardCount(len(notes)),
+				undo: func() error {
+					return m.storage.RestoreNotes(deleted)
+				},
+				redo: func() (err error) {
+					deleted, err = m.storage.DeleteNotes(ids)
+					return err
+				},
+			}, "Deleted "+cardCount(len(notes)))
+		},
+	}
+}
+
+// archiveNotes archives the marked notes, or the selected note when none
+// are marked, taking them off the board
+func (m *KanbanModel) archiveNotes() tea.Cmd {
+	notes := m.targetNotes()
+	if len(notes) == 0 {
+		return nil
+	}
+	ids := noteIDs(notes)
+
+	desc := "archive " + cardCount(len(notes))
+	status := "Archived " + cardCount(len(notes))
+	if len(notes) == 1 {
+		desc = fmt.Sprintf("archive #%d", notes[0].ID)
+		status = fmt.Sprintf("Archived #%d", notes[0].ID)
+	}
+
+	return m.runOp(&boardOp{
+		desc:   desc,
+		noteID: notes[0].ID,
+		undo: func() error {
+			return m.storage.SetNotesArchived(ids, false)
+		},
+		redo: func() error {
+			return m.storage.SetNotesArchived(ids, true)
+		},
+	}, status)
+}
+
+// noteIDs returns the IDs of notes
+func noteIDs(notes []*storage.Note) []int {
+	ids := make([]int, len(notes))
+	for i, note := range notes {
+		ids[i] = note.ID
+	}
+	return ids
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"cheesebox/internal/stats"
+	"cheesebox/internal/storage"
+)
+
+// sparkLevels are the glyphs of a sparkline, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// RenderOverview renders the stats dashboard: notes per status, daily
+// activity as sparklines, top tags, the oldest open notes and the state of
+// the search index
+func RenderOverview(overview *stats.Overview) string {
+	var output strings.Builder
+	output.WriteString(active.TitleStyle.Render("📊 Backlog overview"))
+	output.WriteString("\n")
+
+	output.WriteString(active.LabelStyle.Render(fmt.Sprintf("Notes (%d)", overview.Total)))
+	output.WriteString("\n")
+	for _, status := range storage.Statuses {
+		count := overview.ByStatus[status]
+		bar := ""
+		if overview.Total > 0 {
+			bar = strings.Repeat("█", (reportBarWidth*count+overview.Total-1)/overview.Total)
+		}
+		output.WriteString(fmt.Sprintf("  %s  %s %s\n", statusStyle(status).Render(fmt.Sprintf("%-8s", strings.ToUpper(status))),
+			statusStyle(status).Render(bar), active.MutedStyle.Render(fmt.Sprintf("%d", count))))
+	}
+	if overview.Archived > 0 {
+		output.WriteString(active.MutedStyle.Render(fmt.Sprintf("  %-8s  %d", "ARCHIVED", overview.Archived)))
+		output.WriteString("\n")
+	}
+	output.WriteString("\n")
+
+	if len(overview.Activity) > 0 {
+		first := overview.Activity[0].Day
+		last := overview.Activity[len(overview.Activity)-1].Day
+		output.WriteString(active.LabelStyle.Render(fmt.Sprintf("Activity, %s – %s", first.Format("Jan 2"), last.Format("Jan 2"))))
+		output.WriteString("\n")
+
+		created := make([]int, len(overview.Activity))
+		completed := make([]int, len(overview.Activity))
+		var createdTotal, completedTotal int
+		for i, day := range overview.Activity {
+			created[i], completed[i] = day.Created, day.Completed
+			createdTotal += day.Created
+			completedTotal += day.Completed
+		}
+		output.WriteString(fmt.Sprintf("  %s  %s %s\n", active.ContentStyle.Render("Created  "),
+			active.BarStyle.Render(sparkline(created)), active.MutedStyle.Render(fmt.Sprintf("%d", createdTotal))))
+		output.WriteString(fmt.Sprintf("  %s  %s %s\n", active.ContentStyle.Render("Completed"),
+			statusStyle("done").Render(sparkline(completed)), active.MutedStyle.Render(fmt.Sprintf("%d", completedTotal))))
+		output.WriteString("\n")
+	}
+
+	output.WriteString(active.LabelStyle.Render("Top tags"))
+	output.WriteString("\n")
+	if len(overview.TopTags) == 0 {
+		output.WriteString(active.MutedStyle.Render("  No tags yet"))
+		output.WriteString("\n")
+	}
+	tagWidth := 0
+	for _, tag := range overview.TopTags {
+		if w := runewidth.StringWidth(tag.Tag) + 1; w > tagWidth {
+			tagWidth = w
+		}
+	}
+	for _, tag := range overview.TopTags {
+		bar := strings.Repeat("█", (reportBarWidth*tag.Count+overview.TopTags[0].Count-1)/overview.TopTags[0].Count)
+		output.WriteString(fmt.Sprintf("  %s  %s %s\n", active.ContentStyle.Render(runewidth.FillRight("#"+tag.Tag, tagWidth)),
+			active.BarStyle.Render(bar), active.MutedStyle.Render(fmt.Sprintf("%d", tag.Count))))
+	}
+	output.WriteString("\n")
+
+	output.WriteString(active.LabelStyle.Render("Oldest open notes"))
+	output.WriteString("\n")
+	if len(overview.Oldest) == 0 {
+		output.WriteString(active.MutedStyle.Render("  Nothing open"))
+		output.WriteString("\n")
+	}
+	for _, note := range overview.Oldest {
+		content := truncate(note.Title, 50)
+		output.WriteString(fmt.Sprintf("  %-5s %s %s %s\n", fmt.Sprintf("#%d", note.ID),
+			active.MutedStyle.Render(fmt.Sprintf("%-8s", formatSpan(time.Since(note.CreatedAt)))),
+			statusStyle(note.Status).Render(fmt.Sprintf("%-5s", strings.ToUpper(note.Status))), active.ContentStyle.Render(content)))
+	}
+	output.WriteString("\n")
+
+	output.WriteString(active.LabelStyle.Render("Storage"))
+	output.WriteString("\n")
+	coverage := fmt.Sprintf("%d of %d notes (%.0f%%)", overview.Embedded, overview.Total, overview.Coverage*100)
+	if overview.Embedded < overview.Total {
+		coverage += active.MutedStyle.Render(" · run cx embed to index the rest")
+	}
+	output.WriteString(fmt.Sprintf("  %s  %s\n", active.ContentStyle.Render("Embeddings"), coverage))
+	output.WriteString(fmt.Sprintf("  %s  %s", active.ContentStyle.Render("Database  "), formatBytes(overview.DatabaseSize)))
+	return output.String()
+}
+
+// sparkline draws values as a row of bars scaled to the largest. Zero
+// always draws the lowest bar and any other value at least the next one
+// up, so quiet days stay visible.
+func sparkline(values []int) string {
+	most := 0
+	for _, value := range values {
+		if value > most {
+			most = value
+		}
+	}
+
+	var line strings.Builder
+	for _, value := range values {
+		level := 0
+		if most > 0 && value > 0 {
+			level = 1 + (len(sparkLevels)-2)*value/most
+		}
+		line.WriteRune(sparkLevels[level])
+	}
+	return line.String()
+}
+
+// formatBytes formats a size in bytes with a binary unit, like 1.5 MB
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
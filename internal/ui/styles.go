@@ -6,105 +6,121 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 	"cheesebox/internal/storage"
 )
 
-// Color palette
-var (
-	// Primary colors
-	primaryColor   = lipgloss.Color("#FF6B6B") // Coral red
-	secondaryColor = lipgloss.Color("#4ECDC4") // Teal
-	accentColor    = lipgloss.Color("#45B7D1") // Light blue
-	
-	// Status colors
-	todoColor  = lipgloss.Color("#FFA726")   // Orange
-	doingColor = lipgloss.Color("#66BB6A")   // Green
-	doneColor  = lipgloss.Color("#9E9E9E")   // Gray
-	
-	// UI colors
-	textColor      = lipgloss.Color("#2C3E50") // Dark blue-gray
-	mutedColor     = lipgloss.Color("#7F8C8D") // Gray
-	borderColor    = lipgloss.Color("#BDC3C7") // Light gray
-	errorColor     = lipgloss.Color("#E74C3C") // Red
-	successColor   = lipgloss.Color("#27AE60") // Green
-	
-	// Background colors
-	bgColor        = lipgloss.Color("#FFFFFF") // White
-	altBgColor     = lipgloss.Color("#F8F9FA") // Light gray
-)
+// active is the theme the interface is drawn with, set by SetTheme
+var active Theme
+
+func init() {
+	SetTheme(Themes[DefaultTheme])
+}
 
-// Base styles
-var (
-	// Title styles
-	titleStyle = lipgloss.NewStyle().
-			Foreground(primaryColor).
+// buildStyles builds the theme's styles from its colours. Without colour,
+// highlights use reverse video so the selection stays visible.
+func (t *Theme) buildStyles() {
+	t.TitleStyle = lipgloss.NewStyle().
+			Foreground(t.Primary).
 			Bold(true).
 			MarginBottom(1)
 	
-	headerStyle = lipgloss.NewStyle().
-			Foreground(secondaryColor).
+	t.HeaderStyle = lipgloss.NewStyle().
+			Foreground(t.Secondary).
 			Bold(true).
 			MarginBottom(1)
 	
-	// Text styles
-	contentStyle = lipgloss.NewStyle().
-			Foreground(textColor)
+	t.LabelStyle = lipgloss.NewStyle().
+			Foreground(t.Secondary).
+			Bold(true)
+	
+	t.ContentStyle = lipgloss.NewStyle().
+			Foreground(t.Text)
 	
-	mutedStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
+	t.MutedStyle = lipgloss.NewStyle().
+			Foreground(t.Muted).
 			Italic(true)
 	
-	// Status styles
-	todoStyle = lipgloss.NewStyle().
-			Foreground(todoColor).
+	t.TodoStyle = lipgloss.NewStyle().
+			Foreground(t.Todo).
 			Bold(true)
 	
-	doingStyle = lipgloss.NewStyle().
-			Foreground(doingColor).
+	t.DoingStyle = lipgloss.NewStyle().
+			Foreground(t.Doing).
 			Bold(true)
 	
-	doneStyle = lipgloss.NewStyle().
-			Foreground(doneColor).
+	t.DoneStyle = lipgloss.NewStyle().
+			Foreground(t.Done).
 			Bold(true)
 	
-	// UI element styles
-	borderStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(borderColor).
+	t.BorderStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(t.Border).
 			Padding(1, 2)
 	
-	cardStyle = lipgloss.NewStyle().
+	t.CardStyle = lipgloss.NewStyle().
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(borderColor).
+			BorderForeground(t.Border).
 			Padding(1, 2).
 			MarginBottom(1)
 	
-	highlightStyle = lipgloss.NewStyle().
-			Background(accentColor).
-			Foreground(bgColor).
+	t.HighlightStyle = lipgloss.NewStyle().
+			Background(t.Accent).
+			Foreground(t.Background).
 			Bold(true).
 			Padding(0, 1)
 	
-	// Message styles
-	errorStyle = lipgloss.NewStyle().
-			Foreground(errorColor).
+	t.KeyStyle = lipgloss.NewStyle().
+			Foreground(t.Accent).
 			Bold(true)
 	
-	successStyle = lipgloss.NewStyle().
-			Foreground(successColor).
+	t.MarkedStyle = lipgloss.NewStyle().
+			Foreground(t.Secondary).
 			Bold(true)
 	
-	warningStyle = lipgloss.NewStyle().
-			Foreground(todoColor).
+	t.ErrorStyle = lipgloss.NewStyle().
+			Foreground(t.Error).
 			Bold(true)
-)
+	
+	t.SuccessStyle = lipgloss.NewStyle().
+			Foreground(t.Success).
+			Bold(true)
+	
+	t.WarningStyle = lipgloss.NewStyle().
+			Foreground(t.Todo).
+			Bold(true)
+	
+	t.WIPExceededStyle = lipgloss.NewStyle().
+			Foreground(t.Error).
+			Bold(true).
+			MarginBottom(1)
+	
+	t.WIPExceededHighlightStyle = lipgloss.NewStyle().
+			Background(t.Error).
+			Foreground(t.Background).
+			Bold(true).
+			Padding(0, 1)
+	
+	t.BarStyle = lipgloss.NewStyle().
+			Foreground(t.Accent)
+	
+	if t.NoColor {
+		t.HighlightStyle = t.HighlightStyle.Reverse(true)
+		t.WIPExceededHighlightStyle = t.WIPExceededHighlightStyle.Reverse(true)
+		t.MarkedStyle = t.MarkedStyle.Underline(true)
+		t.WIPExceededStyle = t.WIPExceededStyle.Underline(true)
+	}
+	
+	t.buildMarkdownStyles()
+	t.buildDetailStyles()
+}
 
 // RenderNotesList renders a formatted list of notes
 func RenderNotesList(notes []*storage.Note, title string) string {
 	var output strings.Builder
 	
 	// Title
-	output.WriteString(titleStyle.Render("📋 " + title))
+	output.WriteString(active.TitleStyle.Render("📋 " + title))
 	output.WriteString("\n\n")
 	
 	// Notes
@@ -117,7 +133,7 @@ func RenderNotesList(notes []*storage.Note, title string) string {
 	
 	// Footer
 	output.WriteString("\n\n")
-	output.WriteString(mutedStyle.Render(fmt.Sprintf("Total: %d notes", len(notes))))
+	output.WriteString(active.MutedStyle.Render(fmt.Sprintf("Total: %d notes", len(notes))))
 	
 	return output.String()
 }
@@ -131,16 +147,24 @@ func renderNote(note *storage.Note, isFirst bool) string {
 	if note.Status != "" {
 		header += " " + renderStatus(note.Status)
 	}
-	output.WriteString(headerStyle.Render(header))
+	if note.Priority != nil {
+		header += " " + renderPriority(note.Priority)
+	}
+	output.WriteString(active.HeaderStyle.Render(header))
 	output.WriteString("\n")
 	
-	// Content
-	content := note.Content
-	if len(content) > 80 {
-		content = content[:77] + "..."
+	// Title, in red once overdue, and the start of the body
+	title := truncate(note.Title, 80)
+	if note.IsOverdue(time.Now()) {
+		output.WriteString(active.ErrorStyle.Render(title))
+	} else {
+		output.WriteString(active.ContentStyle.Render(title))
 	}
-	output.WriteString(contentStyle.Render(content))
 	output.WriteString("\n")
+	if note.Content != "" {
+		output.WriteString(active.MutedStyle.Render(truncate(strings.Join(strings.Fields(note.Content), " "), 80)))
+		output.WriteString("\n")
+	}
 	
 	// Metadata row
 	var metadata []string
@@ -149,6 +173,14 @@ func renderNote(note *storage.Note, isFirst bool) string {
 	timeStr := formatTime(note.UpdatedAt)
 	metadata = append(metadata, "⏰ "+timeStr)
 	
+	// Due and scheduled days
+	if dates := noteDates(note, startOfDay(time.Now())); len(dates) > 0 {
+		metadata = append(metadata, "📅 "+strings.Join(dates, ", "))
+	}
+	if note.Recurrence != "" {
+		metadata = append(metadata, "🔁 every:"+note.Recurrence)
+	}
+	
 	// Tags
 	if len(note.Tags) > 0 {
 		tagStr := "🏷️  " + strings.Join(note.Tags, ", ")
@@ -156,23 +188,60 @@ func renderNote(note *storage.Note, isFirst bool) string {
 	}
 	
 	if len(metadata) > 0 {
-		output.WriteString(mutedStyle.Render(strings.Join(metadata, " • ")))
+		output.WriteString(active.MutedStyle.Render(strings.Join(metadata, " • ")))
 	}
 	
-	return cardStyle.Render(output.String())
+	return active.CardStyle.Render(output.String())
+}
+
+// statusStyle returns the style for a status
+func statusStyle(status string) lipgloss.Style {
+	switch status {
+	case "todo":
+		return active.TodoStyle
+	case "doing":
+		return active.DoingStyle
+	case "done":
+		return active.DoneStyle
+	default:
+		return active.MutedStyle
+	}
 }
 
 // renderStatus renders a status badge with appropriate color
 func renderStatus(status string) string {
 	switch status {
 	case "todo":
-		return todoStyle.Render("TODO")
+		return active.TodoStyle.Render("TODO")
 	case "doing":
-		return doingStyle.Render("DOING")
+		return active.DoingStyle.Render("DOING")
 	case "done":
-		return doneStyle.Render("DONE")
+		return active.DoneStyle.Render("DONE")
 	default:
-		return mutedStyle.Render("UNKNOWN")
+		return active.MutedStyle.Render("UNKNOWN")
+	}
+}
+
+// renderPriority renders a priority badge, most urgent in the strongest
+// colour, or "" for none
+func renderPriority(priority *int) string {
+	if priority == nil {
+		return ""
+	}
+	return priorityStyle(*priority).Render(storage.FormatPriority(priority))
+}
+
+// priorityStyle returns the style of a priority badge
+func priorityStyle(priority int) lipgloss.Style {
+	switch priority {
+	case 0:
+		return active.ErrorStyle
+	case 1:
+		return active.WarningStyle
+	case 2:
+		return active.LabelStyle
+	default:
+		return active.MutedStyle.Copy().Italic(false)
 	}
 }
 
@@ -181,7 +250,7 @@ func RenderKanbanBoard(todoNotes, doingNotes, doneNotes []*storage.Note, selecte
 	var output strings.Builder
 	
 	// Title
-	output.WriteString(titleStyle.Render("📊 Kanban Board"))
+	output.WriteString(active.TitleStyle.Render("📊 Kanban Board"))
 	output.WriteString("\n\n")
 	
 	// Column headers
@@ -192,9 +261,9 @@ func RenderKanbanBoard(todoNotes, doingNotes, doneNotes []*storage.Note, selecte
 	for i, col := range columns {
 		header := fmt.Sprintf("%s (%d)", col, columnCounts[i])
 		if i == selectedColumn {
-			header = highlightStyle.Render(header)
+			header = active.HighlightStyle.Render(header)
 		} else {
-			header = headerStyle.Render(header)
+			header = active.HeaderStyle.Render(header)
 		}
 		headers = append(headers, header)
 	}
@@ -211,7 +280,7 @@ func RenderKanbanBoard(todoNotes, doingNotes, doneNotes []*storage.Note, selecte
 	
 	// Instructions
 	output.WriteString("\n\n")
-	instructions := mutedStyle.Render("← → to navigate columns • ↑ ↓ to select notes • Enter to move • q to quit")
+	instructions := active.MutedStyle.Render("← → to navigate columns • ↑ ↓ to select notes • Enter to move • q to quit")
 	output.WriteString(instructions)
 	
 	return output.String()
@@ -226,15 +295,12 @@ func renderKanbanColumn(notes []*storage.Note, isSelected bool) string {
 	
 	for i, note := range notes {
 		if i >= columnHeight-2 { // Leave space for "..." indicator
-			content.WriteString(mutedStyle.Render("..."))
+			content.WriteString(active.MutedStyle.Render("..."))
 			break
 		}
 		
 		// Truncate content to fit column
-		noteContent := note.Content
-		if len(noteContent) > columnWidth-4 {
-			noteContent = noteContent[:columnWidth-7] + "..."
-		}
+		noteContent := truncate(note.Title, columnWidth-4)
 		
 		// Add note with ID
 		content.WriteString(fmt.Sprintf("#%d %s\n", note.ID, noteContent))
@@ -246,9 +312,9 @@ func renderKanbanColumn(notes []*storage.Note, isSelected bool) string {
 	}
 	
 	// Style the column
-	style := borderStyle.Width(columnWidth).Height(columnHeight)
+	style := active.BorderStyle.Width(columnWidth).Height(columnHeight)
 	if isSelected {
-		style = style.BorderForeground(primaryColor)
+		style = style.BorderForeground(active.Primary)
 	}
 	
 	return style.Render(content.String())
@@ -256,6 +322,16 @@ func renderKanbanColumn(notes []*storage.Note, isSelected bool) string {
 
 // Helper functions for consistent formatting
 
+// truncate shortens s to at most width terminal cells, ending in "..."
+// when cut. It counts display width rather than bytes so multibyte and
+// wide characters are never split.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(s, width, "...")
+}
+
 // formatTime formats a time.Time into a human-readable relative time
 func formatTime(t time.Time) string {
 	now := time.Now()
@@ -289,22 +365,22 @@ func formatTime(t time.Time) string {
 
 // RenderError renders an error message with styling
 func RenderError(message string) string {
-	return errorStyle.Render("❌ " + message)
+	return active.ErrorStyle.Render("❌ " + message)
 }
 
 // RenderSuccess renders a success message with styling
 func RenderSuccess(message string) string {
-	return successStyle.Render("✅ " + message)
+	return active.SuccessStyle.Render("✅ " + message)
 }
 
 // RenderWarning renders a warning message with styling
 func RenderWarning(message string) string {
-	return warningStyle.Render("⚠️  " + message)
+	return active.WarningStyle.Render("⚠️  " + message)
 }
 
 // RenderInfo renders an info message with styling
 func RenderInfo(message string) string {
-	return mutedStyle.Render("ℹ️  " + message)
+	return active.MutedStyle.Render("ℹ️  " + message)
 }
 
 // RenderBanner renders the Cheesebox banner
@@ -318,6 +394,6 @@ func RenderBanner() string {
   \_____|_| |_|\___|\___||___/\___|_.__/ \___/_/\_\
 `
 	
-	return titleStyle.Render(banner) + "\n" + 
-		   mutedStyle.Render("Terminal-based notes with kanban boards & semantic search") + "\n"
+	return active.TitleStyle.Render(banner) + "\n" + 
+		   active.MutedStyle.Render("Terminal-based notes with kanban boards & semantic search") + "\n"
 }
\ No newline at end of file
@@ -0,0 +1,367 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"cheesebox/internal/storage"
+)
+
+// Swimlane groupings
+const (
+	laneNone     = ""
+	laneTag      = "tag"      // First tag on the card
+	lanePrefix   = "prefix"   // First tag starting with a prefix, e.g. area/
+	lanePriority = "priority" // Priority: P0 to P3
+	laneAssignee = "assignee" // Assignee tag: #@name
+)
+
+// laneModes is the order the swimlane key cycles through
+var laneModes = []string{laneNone, laneTag, lanePrefix, lanePriority, laneAssignee}
+
+// swimlane is one horizontal group of cards across every column
+type swimlane struct {
+	name  string
+	notes [numColumns][]*storage.Note
+}
+
+// collapsedLane is a lane collapsed with the keyboard, remembered so the
+// same key can expand it again: its cards have gone from the board, so
+// none of them can be selected to say which lane to expand
+type collapsedLane struct {
+	name     string
+	noteID   int // Card selected in the lane before it collapsed
+	selected int // Card selected once it collapsed, 0 for none
+}
+
+// laneRow is one line of the board in swimlane mode: either a lane header
+// or the cards at index within the lane
+type laneRow struct {
+	lane   *swimlane
+	header bool
+	index  int
+}
+
+// ParseSwimlanes parses a swimlane grouping: tag, prefix:<prefix>,
+// priority or assignee. It returns the grouping and tag prefix.
+func ParseSwimlanes(value string) (string, string, error) {
+	mode, prefix, _ := strings.Cut(strings.ToLower(value), ":")
+	switch mode {
+	case laneNone, laneTag, lanePriority, laneAssignee:
+		return mode, "", nil
+	case lanePrefix:
+		if prefix == "" {
+			return "", "", fmt.Errorf("swimlane prefix cannot be empty, e.g. prefix:area/")
+		}
+		return mode, prefix, nil
+	default:
+		return "", "", fmt.Errorf("unknown swimlane grouping %q (use tag, prefix:<prefix>, priority or assignee)", value)
+	}
+}
+
+// laneName returns the lane a note belongs to under the current grouping,
+// or "" for the catch-all lane
+func (m *KanbanModel) laneName(note *storage.Note) string {
+	switch m.laneMode {
+	case laneTag:
+		if len(note.Tags) > 0 {
+			return note.Tags[0]
+		}
+	case lanePrefix:
+		for _, tag := range note.Tags {
+			if strings.HasPrefix(tag, m.lanePrefix) && tag != m.lanePrefix {
+				return strings.TrimPrefix(tag, m.lanePrefix)
+			}
+		}
+	case lanePriority:
+		return storage.FormatPriority(note.Priority)
+	case laneAssignee:
+		for _, tag := range note.Tags {
+			if strings.HasPrefix(tag, "@") && len(tag) > 1 {
+				return tag
+			}
+		}
+	}
+	return ""
+}
+
+// laneTitle returns the header text for a lane
+func (m *KanbanModel) laneTitle(name string) string {
+	if name != "" {
+		return name
+	}
+	switch m.laneMode {
+	case lanePriority:
+		return "no priority"
+	case laneAssignee:
+		return "unassigned"
+	default:
+		return "untagged"
+	}
+}
+
+// groupSwimlanes splits the filtered columns into lanes, sorted by name
+// with the catch-all lane last, and orders each column lane by lane
+// skipping collapsed lanes so navigation follows the rendered order
+func (m *KanbanModel) groupSwimlanes() {
+	m.swimlanes = nil
+	if m.laneMode == laneNone {
+		return
+	}
+
+	byName := make(map[string]*swimlane)
+	for column := 0; column < numColumns; column++ {
+		for _, note := range m.visible[column] {
+			name := m.laneName(note)
+			lane, ok := byName[name]
+			if !ok {
+				lane = &swimlane{name: name}
+				byName[name] = lane
+				m.swimlanes = append(m.swimlanes, lane)
+			}
+			lane.notes[column] = append(lane.notes[column], note)
+		}
+	}
+
+	sort.Slice(m.swimlanes, func(i, j int) bool {
+		a, b := m.swimlanes[i].name, m.swimlanes[j].name
+		if a == "" || b == "" {
+			return b == ""
+		}
+		return a < b
+	})
+
+	for column := 0; column < numColumns; column++ {
+		var notes []*storage.Note
+		for _, lane := range m.swimlanes {
+			if !m.collapsedLanes[lane.name] {
+				notes = append(notes, lane.notes[column]...)
+			}
+		}
+		m.visible[column] = notes
+	}
+}
+
+// laneRows lays the lanes out as board rows: a header per lane followed by
+// as many rows as the lane's fullest column, unless the lane is collapsed
+func (m *KanbanModel) laneRows() []laneRow {
+	var rows []laneRow
+	for _, lane := range m.swimlanes {
+		rows = append(rows, laneRow{lane: lane, header: true})
+		if m.collapsedLanes[lane.name] {
+			continue
+		}
+
+		height := 0
+		for column := 0; column < numColumns; column++ {
+			if n := len(lane.notes[column]); n > height {
+				height = n
+			}
+		}
+		for i := 0; i < height; i++ {
+			rows = append(rows, laneRow{lane: lane, index: i})
+		}
+	}
+	return rows
+}
+
+// selectedLaneRow returns the board row holding the selected card, or -1
+func (m *KanbanModel) selectedLaneRow(rows []laneRow) int {
+	selected := m.selectedNoteOrNil()
+	if selected == nil {
+		return -1
+	}
+	for i, row := range rows {
+		notes := row.lane.notes[m.selectedColumn]
+		if !row.header && row.index < len(notes) && notes[row.index] == selected {
+			return i
+		}
+	}
+	return -1
+}
+
+// renderLaneColumns renders the three columns in swimlane mode. Lanes line
+// up across columns and the columns scroll together.
+func (m *KanbanModel) renderLaneColumns(columnWidth, rows int) string {
+	boardRows := m.laneRows()
+	offset := m.laneScroll
+	end := offset + rows
+	if end > len(boardRows) {
+		end = len(boardRows)
+	}
+
+	var columns []string
+	for column := 0; column < numColumns; column++ {
+		var lines []string
+		above, below := 0, 0
+		index := 0 // Position of the next card in m.visible[column]
+
+		for i, row := range boardRows {
+			notes := row.lane.notes[column]
+			hasCard := !row.header && row.index < len(notes)
+
+			switch {
+			case i < offset:
+				if hasCard {
+					above++
+				}
+			case i >= end:
+				if hasCard {
+					below++
+				}
+			case row.header:
+				lines = append(lines, m.renderLaneHeader(row.lane, column, columnWidth))
+			case hasCard:
+				lines = append(lines, m.renderCard(notes[row.index], column, index, columnWidth))
+			default:
+				lines = append(lines, "")
+			}
+
+			if hasCard {
+				index++
+			}
+		}
+
+		columns = append(columns, m.renderColumnFrame(lines, column, columnWidth, rows, above, below))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+}
+
+// renderLaneHeader renders a lane's header cell with its count in column
+func (m *KanbanModel) renderLaneHeader(lane *swimlane, column, width int) string {
+	marker := "▾"
+	if m.collapsedLanes[lane.name] {
+		marker = "▸"
+	}
+	header := fmt.Sprintf("%s %s (%d)", marker, m.laneTitle(lane.name), len(lane.notes[column]))
+	return active.LabelStyle.Render(truncate(header, width-4))
+}
+
+// cycleSwimlanes switches to the next swimlane grouping, asking for the
+// tag prefix when switching to prefix lanes
+func (m *KanbanModel) cycleSwimlanes() tea.Cmd {
+	next := laneNone
+	for i, mode := range laneModes {
+		if mode == m.laneMode {
+			next = laneModes[(i+1)%len(laneModes)]
+		}
+	}
+
+	if next == lanePrefix {
+		cmd := m.openInput("Swimlane tag prefix (Esc to skip):", m.lanePrefix, func(prefix string) tea.Cmd {
+			if prefix == "" {
+				m.statusMsg = "Swimlane prefix cannot be empty"
+				return nil
+			}
+			m.setSwimlanes(lanePrefix, strings.ToLower(strings.TrimPrefix(prefix, "#")))
+			return nil
+		})
+		// Skipping the prompt moves on to the grouping after prefix lanes
+		m.input.cancel = func() {
+			m.setSwimlanes(lanePriority, m.lanePrefix)
+		}
+		return cmd
+	}
+
+	m.setSwimlanes(next, m.lanePrefix)
+	return nil
+}
+
+// setSwimlanes regroups the board, keeping the selected card selected
+func (m *KanbanModel) setSwimlanes(mode, prefix string) {
+	if selected := m.selectedNoteOrNil(); selected != nil {
+		m.focusNoteID = selected.ID
+	}
+	m.laneMode = mode
+	m.lanePrefix = prefix
+	m.laneScroll = 0
+	m.applyFilter()
+	m.restoreSelection()
+
+	switch mode {
+	case laneNone:
+		m.statusMsg = "Swimlanes off"
+	case lanePrefix:
+		m.statusMsg = fmt.Sprintf("Swimlanes by tag prefix %q", prefix)
+	default:
+		m.statusMsg = "Swimlanes by " + mode
+	}
+}
+
+// toggleSelectedLane collapses the selected card's lane. Pressed again
+// before the selection moves, it expands that lane again, selecting the
+// card it was collapsed from; with no card to select, it expands the
+// collapsed lane nearest the top of the board's view.
+func (m *KanbanModel) toggleSelectedLane() {
+	if m.laneMode == laneNone {
+		return
+	}
+
+	selectedID := 0
+	selected := m.selectedNoteOrNil()
+	if selected != nil {
+		selectedID = selected.ID
+	}
+
+	if last := m.lastCollapsed; last != nil && last.selected == selectedID && m.collapsedLanes[last.name] {
+		m.lastCollapsed = nil
+		m.focusNoteID = last.noteID
+		m.toggleLane(last.name)
+		return
+	}
+	m.lastCollapsed = nil
+
+	if selected == nil {
+		if name, ok := m.nearestCollapsedLane(); ok {
+			m.toggleLane(name)
+		}
+		return
+	}
+
+	name := m.laneName(selected)
+	m.focusNoteID = selected.ID
+	m.toggleLane(name)
+
+	m.lastCollapsed = &collapsedLane{name: name, noteID: selected.ID}
+	if now := m.selectedNoteOrNil(); now != nil {
+		m.lastCollapsed.selected = now.ID
+	}
+}
+
+// nearestCollapsedLane returns the first collapsed lane at or below the
+// top of the board's view, or the last one above it
+func (m *KanbanModel) nearestCollapsedLane() (string, bool) {
+	name, found := "", false
+	for i, row := range m.laneRows() {
+		if !row.header || !m.collapsedLanes[row.lane.name] {
+			continue
+		}
+		name, found = row.lane.name, true
+		if i >= m.laneScroll {
+			break
+		}
+	}
+	return name, found
+}
+
+// toggleLane collapses or expands a lane by name. The card to keep
+// selected is set in focusNoteID beforehand, if any.
+func (m *KanbanModel) toggleLane(name string) {
+	m.collapsedLanes[name] = !m.collapsedLanes[name]
+	m.applyFilter()
+	m.restoreSelection()
+}
+
+// expandAllLanes expands every collapsed lane
+func (m *KanbanModel) expandAllLanes() {
+	if selected := m.selectedNoteOrNil(); selected != nil {
+		m.focusNoteID = selected.ID
+	}
+	m.collapsedLanes = make(map[string]bool)
+	m.applyFilter()
+	m.restoreSelection()
+}
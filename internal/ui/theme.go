@@ -0,0 +1,301 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"cheesebox/internal/config"
+)
+
+// DefaultTheme is the theme used when none is configured
+const DefaultTheme = "auto"
+
+// Theme holds every semantic colour and style the interface is drawn
+// with. Colours may be lipgloss.AdaptiveColor to follow the terminal's
+// background. Themes are defined by their colours; SetTheme builds the
+// styles from them.
+type Theme struct {
+	Primary   lipgloss.TerminalColor // Titles and the focused column
+	Secondary lipgloss.TerminalColor // Headers and labels
+	Accent    lipgloss.TerminalColor // Selection and the detail pane
+
+	Todo  lipgloss.TerminalColor
+	Doing lipgloss.TerminalColor
+	Done  lipgloss.TerminalColor
+
+	Text       lipgloss.TerminalColor
+	Muted      lipgloss.TerminalColor
+	Border     lipgloss.TerminalColor
+	Error      lipgloss.TerminalColor
+	Success    lipgloss.TerminalColor
+	Background lipgloss.TerminalColor // Text on highlighted backgrounds
+
+	// NoColor draws without colour, marking the selection with reverse video
+	NoColor bool
+
+	// Titles, headers and labels; LabelStyle is HeaderStyle for use
+	// inline, without the bottom margin
+	TitleStyle  lipgloss.Style
+	HeaderStyle lipgloss.Style
+	LabelStyle  lipgloss.Style
+
+	// Text
+	ContentStyle lipgloss.Style
+	MutedStyle   lipgloss.Style
+
+	// Statuses
+	TodoStyle  lipgloss.Style
+	DoingStyle lipgloss.Style
+	DoneStyle  lipgloss.Style
+
+	// Frames, cards and the selection
+	BorderStyle    lipgloss.Style
+	CardStyle      lipgloss.Style
+	HighlightStyle lipgloss.Style
+	DetailStyle    lipgloss.Style // The kanban card detail pane
+
+	// Keys in the help overlay and cards marked for bulk actions
+	KeyStyle    lipgloss.Style
+	MarkedStyle lipgloss.Style
+
+	// Messages
+	ErrorStyle   lipgloss.Style
+	SuccessStyle lipgloss.Style
+	WarningStyle lipgloss.Style
+
+	// Kanban column headers over their WIP limit
+	WIPExceededStyle          lipgloss.Style
+	WIPExceededHighlightStyle lipgloss.Style
+
+	// Bars in reports and charts
+	BarStyle lipgloss.Style
+
+	// Markdown in the detail pane and the browser preview
+	MarkdownHeadingStyle lipgloss.Style
+	MarkdownCodeStyle    lipgloss.Style
+	MarkdownQuoteStyle   lipgloss.Style
+	MarkdownBoldStyle    lipgloss.Style
+	MarkdownItalicStyle  lipgloss.Style
+}
+
+// adaptive returns a colour that follows the terminal background
+func adaptive(light, dark string) lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: light, Dark: dark}
+}
+
+// Themes are the built-in themes by name
+var Themes = map[string]Theme{
+	// auto picks light or dark colours from the terminal background
+	"auto": {
+		Primary:    lipgloss.Color("#FF6B6B"),
+		Secondary:  adaptive("#16A085", "#4ECDC4"),
+		Accent:     adaptive("#2E86C1", "#45B7D1"),
+		Todo:       adaptive("#E67E22", "#FFA726"),
+		Doing:      adaptive("#27AE60", "#66BB6A"),
+		Done:       adaptive("#7F8C8D", "#9E9E9E"),
+		Text:       adaptive("#2C3E50", "#ECEFF1"),
+		Muted:      adaptive("#7F8C8D", "#95A5A6"),
+		Border:     adaptive("#BDC3C7", "#546E7A"),
+		Error:      adaptive("#E74C3C", "#EF5350"),
+		Success:    adaptive("#27AE60", "#66BB6A"),
+		Background: adaptive("#FFFFFF", "#1E1E1E"),
+	},
+	"light": {
+		Primary:    lipgloss.Color("#FF6B6B"),
+		Secondary:  lipgloss.Color("#4ECDC4"),
+		Accent:     lipgloss.Color("#45B7D1"),
+		Todo:       lipgloss.Color("#FFA726"),
+		Doing:      lipgloss.Color("#66BB6A"),
+		Done:       lipgloss.Color("#9E9E9E"),
+		Text:       lipgloss.Color("#2C3E50"),
+		Muted:      lipgloss.Color("#7F8C8D"),
+		Border:     lipgloss.Color("#BDC3C7"),
+		Error:      lipgloss.Color("#E74C3C"),
+		Success:    lipgloss.Color("#27AE60"),
+		Background: lipgloss.Color("#FFFFFF"),
+	},
+	"dark": {
+		Primary:    lipgloss.Color("#FF6B6B"),
+		Secondary:  lipgloss.Color("#4ECDC4"),
+		Accent:     lipgloss.Color("#45B7D1"),
+		Todo:       lipgloss.Color("#FFA726"),
+		Doing:      lipgloss.Color("#66BB6A"),
+		Done:       lipgloss.Color("#9E9E9E"),
+		Text:       lipgloss.Color("#ECEFF1"),
+		Muted:      lipgloss.Color("#95A5A6"),
+		Border:     lipgloss.Color("#546E7A"),
+		Error:      lipgloss.Color("#EF5350"),
+		Success:    lipgloss.Color("#66BB6A"),
+		Background: lipgloss.Color("#1E1E1E"),
+	},
+	// high-contrast uses the bright ANSI colours and black or white text
+	"high-contrast": {
+		Primary:    lipgloss.Color("9"),
+		Secondary:  lipgloss.Color("14"),
+		Accent:     adaptive("0", "15"),
+		Todo:       lipgloss.Color("11"),
+		Doing:      lipgloss.Color("10"),
+		Done:       adaptive("0", "15"),
+		Text:       adaptive("0", "15"),
+		Muted:      adaptive("0", "15"),
+		Border:     adaptive("0", "15"),
+		Error:      lipgloss.Color("9"),
+		Success:    lipgloss.Color("10"),
+		Background: adaptive("15", "0"),
+	},
+	// solarized follows Ethan Schoonover's palette, light or dark to match
+	// the terminal
+	"solarized": {
+		Primary:    lipgloss.Color("#CB4B16"),
+		Secondary:  lipgloss.Color("#2AA198"),
+		Accent:     lipgloss.Color("#268BD2"),
+		Todo:       lipgloss.Color("#B58900"),
+		Doing:      lipgloss.Color("#859900"),
+		Done:       adaptive("#93A1A1", "#586E75"),
+		Text:       adaptive("#657B83", "#839496"),
+		Muted:      adaptive("#93A1A1", "#586E75"),
+		Border:     adaptive("#EEE8D5", "#073642"),
+		Error:      lipgloss.Color("#DC322F"),
+		Success:    lipgloss.Color("#859900"),
+		Background: adaptive("#FDF6E3", "#002B36"),
+	},
+}
+
+// ThemeNames returns the built-in theme names in order
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveTheme returns the built-in or custom theme called name, where
+// custom themes are taken from the config file. An empty name selects
+// DefaultTheme. Setting the NO_COLOR environment variable turns colour
+// off whatever the theme.
+func ResolveTheme(name string, custom map[string]config.Theme) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+
+	theme, err := lookupTheme(name, custom, nil)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		theme.NoColor = true
+	}
+	return theme, nil
+}
+
+// lookupTheme finds a theme by name, applying a custom theme's colours on
+// top of its base. seen guards against custom themes basing on each other
+// in a loop.
+func lookupTheme(name string, custom map[string]config.Theme, seen map[string]bool) (Theme, error) {
+	ct, ok := custom[name]
+	if !ok {
+		theme, ok := Themes[name]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown theme %q (built-in themes: %s)", name, strings.Join(ThemeNames(), ", "))
+		}
+		return theme, nil
+	}
+
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	if seen[name] {
+		return Theme{}, fmt.Errorf("theme %q is based on itself", name)
+	}
+	seen[name] = true
+
+	base := ct.Base
+	if base == "" {
+		base = DefaultTheme
+	}
+
+	var theme Theme
+	if base == name {
+		// A custom theme may adjust the built-in theme it shadows
+		builtin, ok := Themes[name]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme %q is based on itself", name)
+		}
+		theme = builtin
+	} else {
+		var err error
+		theme, err = lookupTheme(base, custom, seen)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme %q: %w", name, err)
+		}
+	}
+
+	for colorName, value := range ct.Colors {
+		target := theme.color(colorName)
+		if target == nil {
+			return Theme{}, fmt.Errorf("theme %q: unknown colour %q (use %s)", name, colorName, strings.Join(themeColorNames, ", "))
+		}
+		if value.Light == value.Dark {
+			*target = lipgloss.Color(value.Light)
+		} else {
+			*target = adaptive(value.Light, value.Dark)
+		}
+	}
+	return theme, nil
+}
+
+// themeColorNames are the colour names custom themes can set
+var themeColorNames = []string{
+	"primary", "secondary", "accent", "todo", "doing", "done",
+	"text", "muted", "border", "error", "success", "background",
+}
+
+// color returns the theme field for a colour name, or nil if unknown
+func (t *Theme) color(name string) *lipgloss.TerminalColor {
+	switch name {
+	case "primary":
+		return &t.Primary
+	case "secondary":
+		return &t.Secondary
+	case "accent":
+		return &t.Accent
+	case "todo":
+		return &t.Todo
+	case "doing":
+		return &t.Doing
+	case "done":
+		return &t.Done
+	case "text":
+		return &t.Text
+	case "muted":
+		return &t.Muted
+	case "border":
+		return &t.Border
+	case "error":
+		return &t.Error
+	case "success":
+		return &t.Success
+	case "background":
+		return &t.Background
+	default:
+		return nil
+	}
+}
+
+// SetTheme switches the interface to theme, building its styles from its
+// colours
+func SetTheme(theme Theme) {
+	if theme.NoColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	theme.buildStyles()
+	active = theme
+}
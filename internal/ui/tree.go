@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cheesebox/internal/storage"
+)
+
+// RenderTree renders notes as a hierarchy of parents and their subtasks,
+// each parent with its rolled-up progress. Notes whose parent is not among
+// notes are drawn at the top level.
+func RenderTree(notes []*storage.Note, progress map[int]storage.Progress, title string) string {
+	var output strings.Builder
+	output.WriteString(active.TitleStyle.Render("🌳 " + title))
+	output.WriteString("\n")
+
+	if len(notes) == 0 {
+		output.WriteString(active.MutedStyle.Render("No subtasks yet. Add one with: cx add \"...\" --parent <id>"))
+		return output.String()
+	}
+
+	present := make(map[int]bool)
+	for _, note := range notes {
+		present[note.ID] = true
+	}
+	var roots []*storage.Note
+	children := make(map[int][]*storage.Note)
+	for _, note := range notes {
+		if note.ParentID != 0 && present[note.ParentID] {
+			children[note.ParentID] = append(children[note.ParentID], note)
+		} else {
+			roots = append(roots, note)
+		}
+	}
+	byCreation := func(list []*storage.Note) {
+		sort.SliceStable(list, func(i, j int) bool {
+			if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+				return list[i].CreatedAt.Before(list[j].CreatedAt)
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+	byCreation(roots)
+	for _, list := range children {
+		byCreation(list)
+	}
+
+	// render writes a note and its subtasks, indented below indent
+	var render func(note *storage.Note, indent, branch string)
+	render = func(note *storage.Note, indent, branch string) {
+		output.WriteString(active.MutedStyle.Render(indent+branch) + renderTreeNote(note, progress))
+		output.WriteString("\n")
+
+		switch branch {
+		case "├── ":
+			indent += "│   "
+		case "└── ":
+			indent += "    "
+		}
+		for i, child := range children[note.ID] {
+			next := "├── "
+			if i == len(children[note.ID])-1 {
+				next = "└── "
+			}
+			render(child, indent, next)
+		}
+	}
+	for i, root := range roots {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+		render(root, "", "")
+	}
+
+	output.WriteString("\n")
+	output.WriteString(active.MutedStyle.Render(fmt.Sprintf("Total: %d notes", len(notes))))
+	return output.String()
+}
+
+// renderTreeNote renders one line of a tree: the note's ID, status,
+// rolled-up progress and content
+func renderTreeNote(note *storage.Note, progress map[int]storage.Progress) string {
+	line := fmt.Sprintf("#%d %s", note.ID, renderStatus(note.Status))
+	if note.Priority != nil {
+		line += " " + renderPriority(note.Priority)
+	}
+	if p, ok := progress[note.ID]; ok && p.Total > 0 {
+		line += " " + renderProgress(p.Done, p.Total, "◆ ")
+	}
+	content := truncate(note.Title, 60)
+	if note.Status == "done" {
+		return line + " " + active.MutedStyle.Render(content)
+	}
+	return line + " " + active.ContentStyle.Render(content)
+}
+
+// renderProgress renders a done/total badge after marker, green once
+// everything is done
+func renderProgress(done, total int, marker string) string {
+	style := active.MutedStyle
+	if done == total {
+		style = active.SuccessStyle
+	}
+	return style.Render(fmt.Sprintf("%s%d/%d", marker, done, total))
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+)
+
+// watchInterval is how often the board checks the database for changes
+// made by other processes, such as `cx add` in another terminal
+const watchInterval = time.Second
+
+// dataVersionMsg carries the database's data version read on a watch tick
+type dataVersionMsg struct {
+	version int64
+}
+
+// watchChanges reads the database's data version after watchInterval. A
+// failed read is skipped and retried on the next tick.
+func (m *KanbanModel) watchChanges() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		version, err := m.storage.DataVersion()
+		if err != nil {
+			return dataVersionMsg{version: m.dataVersion}
+		}
+		return dataVersionMsg{version: version}
+	})
+}
+
+// handleDataVersion reloads the board when the database changed since the
+// last tick, keeping the selected card selected, and schedules the next tick
+func (m *KanbanModel) handleDataVersion(msg dataVersionMsg) tea.Cmd {
+	if msg.version != m.dataVersion {
+		changed := m.dataVersion != 0
+		m.dataVersion = msg.version
+		if changed {
+			if selected := m.selectedNoteOrNil(); selected != nil {
+				m.focusNoteID = selected.ID
+			}
+			if err := m.loadNotes(); err != nil {
+				m.statusMsg = fmt.Sprintf("Error loading notes: %v", err)
+			}
+		}
+	}
+	return m.watchChanges()
+}